@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/version"
+	"strings"
+)
+
+// runBootstrap adopts a repository without version tags by tagging a baseline commit, optionally
+// backfilling releases at historical points before it.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runBootstrap(args []string) error {
+	fs := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	merges := fs.Bool("merges", false, "Backfill a release at every merge on the first-parent history of the baseline")
+	at := fs.String("at", "", "Backfill releases at these commits, separated by commas")
+	dryRun := fs.Bool("dry-run", false, "Only list the tags that would be created")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		return fmt.Errorf("expected a baseline version and optionally the commit to tag with it (defaults to HEAD)")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	opts := version.BootstrapOptions{Version: positional[0], Commit: "HEAD", Merges: *merges}
+	if len(positional) == 2 {
+		opts.Commit = positional[1]
+	}
+	for _, point := range strings.Split(*at, ",") {
+		if point = strings.TrimSpace(point); point != "" {
+			opts.Points = append(opts.Points, point)
+		}
+	}
+
+	plan, err := version.PlanBootstrap(opts, cfg)
+	if err != nil {
+		return err
+	}
+	for _, planned := range plan.Tags {
+		fmt.Printf("%-12s  %s  %s\n", planned.Tag, shortHash(planned.Commit), planned.Message)
+		fmt.Printf("              %s\n", planned.Reason)
+	}
+	if len(plan.Skipped) > 0 {
+		fmt.Printf("%d point(s) shipped no release-worthy commits and are not tagged.\n", len(plan.Skipped))
+	}
+
+	if *dryRun {
+		fmt.Printf("%d tag(s) would be created.\n", len(plan.Tags))
+		return nil
+	}
+	runID, err := version.ApplyBootstrap(plan, cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %d tag(s) (run %s; revert with `tagger undo`).\n", len(plan.Tags), runID)
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/git"
+	"git-tagger/internal/version"
+	"strings"
+)
+
+// runClassify explains how commits are classified, showing the rule that matched each one.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runClassify(args []string) error {
+	fs := flag.NewFlagSet("classify", flag.ExitOnError)
+	message := fs.String("m", "", "Classify this commit message instead of existing commits")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	classifier, err := version.NewClassifier(cfg)
+	if err != nil {
+		return err
+	}
+
+	if *message != "" {
+		subject, body, _ := strings.Cut(*message, "\n")
+		commit := git.Commit{Subject: subject, Body: strings.TrimSpace(body)}
+		fmt.Println(classifier.Classify(commit))
+		return nil
+	}
+
+	if len(positional) == 0 {
+		positional = []string{"HEAD"}
+	}
+	for _, revRange := range positional {
+		commits, err := git.GetCommits(revRange)
+		if err != nil {
+			return err
+		}
+		for _, commit := range commits {
+			class, err := version.ClassifyUnit(commit, classifier, cfg.History)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%.7s  %-6s  %s\n", commit.Hash, class.Level, commit.Subject)
+			fmt.Printf("         %s\n", class)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/lock"
+	"git-tagger/internal/policy"
+	"git-tagger/internal/preflight"
+	"git-tagger/internal/utils"
+	"git-tagger/internal/version"
+	"sort"
+)
+
+const (
+	exitNoRelease    = 3  // no commits warrant a release
+	exitNotARepo     = 4  // not run inside a git repository
+	exitNoTags       = 5  // no version tags found where one is required
+	exitDetachedHead = 6  // HEAD is not on a branch
+	exitDirtyTree    = 7  // uncommitted changes prevent the operation
+	exitTagExists    = 8  // the tag to create already exists
+	exitLocked       = 9  // another tagging run holds the repository lock
+	exitPreflight    = 10 // other preflight checks failed
+	exitPolicy       = 11 // the branch policy does not allow the tag
+)
+
+// exitCodes maps errors to the exit codes of the program; other errors exit with utils.ExitFailure.
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{version.ErrNoRelease, exitNoRelease},
+	{git.ErrNotARepo, exitNotARepo},
+	{git.ErrNoTags, exitNoTags},
+	{git.ErrDetachedHead, exitDetachedHead},
+	{git.ErrDirtyTree, exitDirtyTree},
+	{git.ErrTagExists, exitTagExists},
+	{lock.ErrLocked, exitLocked},
+	{preflight.ErrFailed, exitPreflight},
+	{policy.ErrNotAllowed, exitPolicy},
+	{version.ErrOutsideLine, exitPolicy},
+}
+
+// configPath is the configuration file given with the global -config flag; empty means the repository default.
+var configPath string
+
+// command is a subcommand of the tagger CLI, invoked as `tagger <name> [args]`.
+type command struct {
+	usage       string                    // argument synopsis shown in the help output
+	description string                    // one-line description shown in the help output
+	run         func(args []string) error // entry point receiving the arguments after the command name
+}
+
+// commands lists all available subcommands by name.
+var commands = map[string]command{
+	"bootstrap": {
+		usage:       "[-merges] [-at commit,...] [-dry-run] <version> [commit]",
+		description: "Adopt an untagged repository by tagging a baseline commit, optionally backfilling earlier releases",
+		run:         runBootstrap,
+	},
+	"bump": {
+		usage:       "[-force] major|minor|patch|prerelease",
+		description: "Tag HEAD with the next major, minor, patch or pre-release version",
+		run:         runBump,
+	},
+	"classify": {
+		usage:       "[-m message] [rev|range ...]",
+		description: "Explain which rule classifies each commit (defaults to HEAD)",
+		run:         runClassify,
+	},
+	"contains": {
+		usage:       "[-pre] <commit>",
+		description: "Print the first release containing a commit (exits 5 if none does yet)",
+		run:         runContains,
+	},
+	"current": {
+		usage:       "[-format template]",
+		description: "Print the latest version reachable from HEAD",
+		run:         runCurrent,
+	},
+	"issues": {
+		usage:       "<range|tag>",
+		description: "List the issues referenced in a range of commits and the version that shipped each one",
+		run:         runIssues,
+	},
+	"list": {
+		usage:       "[-builds] [-channel name]",
+		description: "List all versions sorted by version, with their dates and channels",
+		run:         runList,
+	},
+	"log": {
+		usage:       "[-reasons] <from>..<to>|<tag>",
+		description: "List the classified commits of a range, or of the commits a version shipped",
+		run:         runLog,
+	},
+	"next": {
+		usage:       "[-format template]",
+		description: "Print the version HEAD would be tagged with (exits 3 if no release is needed)",
+		run:         runNext,
+	},
+	"notes": {
+		usage:       "[-from tag] [-template file] [-output file] <tag>",
+		description: "Render the release notes of a version tag as Markdown or through a template",
+		run:         runNotes,
+	},
+	"promote": {
+		usage:       "[1.0.0]",
+		description: "End initial development by tagging HEAD with 1.0.0",
+		run:         runPromote,
+	},
+	"prune": {
+		usage:       "[-keep-per-minor n] [-superseded] [-older-than age] [-remote name] [-dry-run]",
+		description: "Delete hash-suffixed build tags according to a retention policy",
+		run:         runPrune,
+	},
+	"set": {
+		usage:       "[-force] <version>",
+		description: "Tag HEAD with an explicit version",
+		run:         runSet,
+	},
+	"tui": {
+		usage:       "[-branch name] [-output file] [-force]",
+		description: "Review the untagged commits in a full-screen terminal view, then tag or export them",
+		run:         runTUI,
+	},
+	"undo": {
+		usage:       "[-remote name] [-list] [run-id]",
+		description: "Delete the tags created by a tagging run (defaults to the latest run)",
+		run:         runUndo,
+	},
+}
+
+// printCommands prints the available subcommands and their descriptions.
+func printCommands() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Available commands:")
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Printf("  %s %s: %s\n", name, cmd.usage, cmd.description)
+	}
+}
+
+// parseInterspersed parses flags that may appear before or after positional arguments
+// (e.g. `tagger bump minor -force`), returning the positional arguments.
+// parameters:
+// - fs: the flag set to parse into
+// - args: the command-line arguments to parse
+// returns:
+// - []string: the positional arguments in order
+// - error: an error object if parsing failed, otherwise nil
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// loadConfig loads the configuration given with -config, or the repository's configuration file.
+// returns:
+// - *config.Config: the loaded configuration
+// - error: an error object if something went wrong, otherwise nil
+func loadConfig() (*config.Config, error) {
+	if configPath != "" {
+		return config.Load(configPath)
+	}
+	return config.LoadFromRepo()
+}
+
+// exitCode returns the exit code of the program for an error.
+// parameters:
+// - err: the error the program fails with
+// returns:
+// - int: the exit code of the first matching error in exitCodes, otherwise utils.ExitFailure
+func exitCode(err error) int {
+	for _, e := range exitCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return utils.ExitFailure
+}
+
+// fail logs an error and exits with its exit code.
+// parameters:
+// - context: a description of what failed
+// - err: the error that occurred, or nil
+func fail(context string, err error) {
+	utils.LogAndExit(context, err, exitCode(err))
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"git-tagger/internal/version"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// levels lists the levels of version increment from the highest to the lowest, for summaries.
+var levels = []string{"major", "minor", "patch", "none"}
+
+// runContains prints the first release that contains a commit.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error wrapping git.ErrNoTags if no release contains the commit, otherwise an error object if something went wrong
+func runContains(args []string) error {
+	fs := flag.NewFlagSet("contains", flag.ExitOnError)
+	pre := fs.Bool("pre", false, "Count pre-releases as releases")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one commit")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	release, err := version.FirstRelease(positional[0], cfg, *pre)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\t%s\n", release.Tag, release.Date.Format("2006-01-02"))
+	return nil
+}
+
+// runLog lists the commits of a range with their classification and the version tags pointing at them.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runLog(args []string) error {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	reasons := fs.Bool("reasons", false, "Explain the classification of each commit")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one revision range (e.g. v1.2.0..v1.5.0) or version tag")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// A version tag stands for the commits it shipped; an open end of a range for HEAD, as in git
+	from, to, isRange := strings.Cut(positional[0], "..")
+	switch {
+	case strings.HasPrefix(to, "."):
+		return fmt.Errorf("symmetric ranges are not supported: %s", positional[0])
+	case isRange:
+		from, to = cmp.Or(from, "HEAD"), cmp.Or(to, "HEAD")
+	default:
+		to = from
+		if from, err = version.PreviousTag(to, cfg); err != nil {
+			return err
+		}
+	}
+
+	logged, err := version.LogRange(from, to, cfg)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, c := range logged {
+		tags := ""
+		if len(c.Tags) > 0 {
+			tags = "  (" + strings.Join(c.Tags, ", ") + ")"
+		}
+		fmt.Printf("%.7s  %-5s  %s%s\n", c.Commit.Hash, c.Class.Level, c.Commit.Subject, tags)
+		if *reasons {
+			fmt.Printf("         %s\n", c.Class)
+		}
+		counts[c.Class.Level]++
+	}
+
+	var summary []string
+	for _, level := range levels {
+		if counts[level] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[level], level))
+		}
+	}
+	fmt.Printf("%d commit(s) in %s", len(logged), describeRange(from, to))
+	if len(summary) > 0 {
+		fmt.Printf(": %s", strings.Join(summary, ", "))
+	}
+	fmt.Println()
+	return nil
+}
+
+// runList lists the versions of the repository with their dates and channels.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	builds := fs.Bool("builds", false, "Include hash-suffixed build tags")
+	channel := fs.String("channel", "", "Only list versions of this channel, e.g. stable, rc or build")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	releases, err := version.ListReleases(cfg, *builds || *channel == version.ChannelBuild)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, release := range releases {
+		if *channel != "" && release.Channel != *channel {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%.7s\n", release.Tag, release.Date.Format("2006-01-02"), release.Channel, release.Commit)
+	}
+	return w.Flush()
+}
+
+// describeRange names a range of commits for summaries.
+func describeRange(from, to string) string {
+	if from == "" {
+		return "the history of " + to
+	}
+	return from + ".." + to
+}
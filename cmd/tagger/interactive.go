@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/lock"
+	"git-tagger/internal/prompt"
+	"git-tagger/internal/version"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// selectBranch lets the user choose the branch to tag, defaulting to the current branch.
+// parameters:
+// - p: the prompter
+// - current: the current branch, or empty for a detached HEAD
+// returns:
+// - string: the selected branch
+// - error: an error object if something went wrong, otherwise nil
+func selectBranch(p *prompt.Prompter, current string) (string, error) {
+	all, err := git.GetBranches()
+	if err != nil {
+		return "", fmt.Errorf("failed to get branches: %w", err)
+	}
+	// skip entries such as "(HEAD detached at 1a2b3c4)"
+	branches := slices.DeleteFunc(all, func(b string) bool { return strings.HasPrefix(b, "(") })
+	if len(branches) == 0 {
+		return "", fmt.Errorf("no branches found in the repository")
+	}
+
+	options := make([]string, len(branches))
+	def := 0
+	for i, branch := range branches {
+		options[i] = branch
+		if branch == current {
+			options[i] += " (current)"
+			def = i
+		}
+	}
+	choice, err := p.Select("Select a branch to tag:", options, def)
+	if err != nil {
+		return "", err
+	}
+	return branches[choice], nil
+}
+
+// tagInteractively previews the tags of the untagged commits on a branch, lets the user change the bump
+// of individual commits, and creates the tags once confirmed.
+// parameters:
+// - p: the prompter
+// - branch: the branch to tag
+// - cfg: the repository configuration
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func tagInteractively(p *prompt.Prompter, branch string, cfg *config.Config) error {
+	overrides := map[string]version.Override{}
+	edited := ""
+	for {
+		plan, err := version.PlanWithOverrides(branch, cfg, overrides)
+		if errors.Is(err, version.ErrOutsideLine) && edited != "" {
+			// undo the bump that left the line of the maintenance branch
+			p.Printf("%v\n", err)
+			delete(overrides, edited)
+			edited = ""
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		commits := plan.Commits()
+		if len(commits) == 0 {
+			p.Printf("No untagged commits found on %s.\n", branch)
+			return nil
+		}
+		printPlan(p, plan, cfg)
+
+		answer, err := p.Ask("Create these tags? [y]es, [n]o, [e]dit a bump: ")
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			if len(plan.Tags) == 0 {
+				p.Printf("No commit warrants a release; edit a bump first or answer n.\n")
+				continue
+			}
+			runID, err := applyPreviewed(plan, overrides, cfg)
+			if err != nil {
+				return err
+			}
+			if runID != "" {
+				p.Printf("Created %d tag(s) (run %s; revert with `tagger undo`).\n", len(plan.Tags), runID)
+				return nil
+			}
+			p.Printf("The plan changed while waiting for another tagging run; review it again.\n")
+		case "e", "edit":
+			edited, err = editBump(p, commits, overrides)
+			if err != nil {
+				return err
+			}
+		case "n", "no", "":
+			p.Printf("No tags created.\n")
+			return nil
+		default:
+			p.Printf("Please answer y, n or e.\n")
+		}
+	}
+}
+
+// printPlan lists the commits of a plan with their bump, version and the reason for the bump.
+// parameters:
+// - p: the prompter
+// - plan: the plan
+// - cfg: the repository configuration
+func printPlan(p *prompt.Prompter, plan *version.Plan, cfg *config.Config) {
+	base := plan.Base
+	if base == "" {
+		base = "no version tags"
+	}
+	p.Printf("\nUntagged commits on %s (from %s):\n", plan.Branch, base)
+	for i, c := range plan.Commits() {
+		tag := "-"
+		if c.Version != nil {
+			tag = c.Version.Tag(cfg.Tag.Prefix)
+		}
+		p.Printf("[%d] %s  %-5s  %-12s  %s\n", i+1, shortHash(c.Commit), c.Level, tag, c.Message)
+		p.Printf("      %s\n", c.Reason)
+	}
+	p.Printf("\n")
+}
+
+// editBump asks for a commit and the level of version increment to apply to it.
+// parameters:
+// - p: the prompter
+// - commits: the commits of the plan, numbered from 1 as printed by printPlan
+// - overrides: the chosen levels by commit hash, updated with the answer
+// returns:
+// - string: the hash of the edited commit, or empty if the answer was invalid
+// - error: an error object if reading the answer failed, otherwise nil
+func editBump(p *prompt.Prompter, commits []version.PlannedTag, overrides map[string]version.Override) (string, error) {
+	answer, err := p.Ask(fmt.Sprintf("Commit number (1-%d) and level (major, minor, patch or none), e.g. 1 minor: ", len(commits)))
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(answer)
+	if len(fields) != 2 {
+		p.Printf("Invalid answer: %s\n", answer)
+		return "", nil
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 1 || n > len(commits) {
+		p.Printf("Invalid commit number: %s\n", fields[0])
+		return "", nil
+	}
+	level := strings.ToLower(fields[1])
+	if !slices.Contains([]string{"major", "minor", "patch", "none"}, level) {
+		p.Printf("Invalid level: %s\n", fields[1])
+		return "", nil
+	}
+
+	commit := commits[n-1].Commit
+	overrides[commit] = version.Override{Level: level}
+	return commit, nil
+}
+
+// applyPreviewed creates the tags of a previewed plan while holding the repository lock. The plan is
+// recomputed under the lock and only applied if it still creates the previewed tags.
+// parameters:
+// - previewed: the plan shown to the user
+// - overrides: the choices of the user by commit hash
+// - cfg: the repository configuration
+// returns:
+// - string: the ID of the run journal, or empty if the plan changed and was not applied
+// - error: an error object if something went wrong, otherwise nil
+func applyPreviewed(previewed *version.Plan, overrides map[string]version.Override, cfg *config.Config) (runID string, err error) {
+	l, err := lock.Acquire(context.Background(), cfg.Lock)
+	if err != nil {
+		return "", err
+	}
+	defer func() { err = errors.Join(err, l.Release()) }()
+
+	_, runID, err = version.ApplyUnchanged(previewed, overrides, cfg)
+	if errors.Is(err, version.ErrPlanChanged) {
+		return "", nil
+	}
+	return runID, err
+}
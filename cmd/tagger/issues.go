@@ -0,0 +1,43 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"git-tagger/internal/version"
+	"os"
+	"text/tabwriter"
+)
+
+// runIssues lists the issues referenced in a range of commits and the version that shipped each of them.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runIssues(args []string) error {
+	fs := flag.NewFlagSet("issues", flag.ExitOnError)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one revision range (e.g. v1.0.0..HEAD), version tag or revision")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// A version tag stands for the commits it shipped; any other revision for its whole history
+	shipped, err := version.ListIssues(positional[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, issue := range shipped {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", issue.Issue, cmp.Or(issue.Tag, "unreleased"))
+	}
+	return w.Flush()
+}
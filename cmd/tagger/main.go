@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"git-tagger/internal/git"
 	"git-tagger/internal/hooks"
+	"git-tagger/internal/logging"
+	"git-tagger/internal/policy"
+	"git-tagger/internal/preflight"
+	"git-tagger/internal/prompt"
 	"git-tagger/internal/utils"
 	"git-tagger/internal/version"
 	"os"
@@ -16,67 +21,143 @@ func main() {
 	installFlag := flag.Bool("install", false, "Install the Git post-commit hook")
 	uninstallFlag := flag.Bool("clean", false, "Remove the Git post-commit hook")
 	versionTagFlag := flag.Bool("version-tag", false, "Tag untagged Git commits with version numbers")
+	flag.StringVar(&configPath, "config", "", "Path to the configuration file (default: config.yaml in the repository root)")
+	verboseFlag := flag.Bool("v", false, "Log debug messages and git commands")
+	veryVerboseFlag := flag.Bool("vv", false, "Log debug messages, git commands and their output")
+	quietFlag := flag.Bool("q", false, "Only log warnings and errors")
+	logFormatFlag := flag.String("log-format", os.Getenv("GIT_TAGGER_LOG_FORMAT"), "Log format: text or json (default: text, or $GIT_TAGGER_LOG_FORMAT)")
+	gitTimeoutFlag := flag.Duration("git-timeout", 0, "Maximum duration of each git command, e.g. 30s (default: no limit)")
+	forceFlag := flag.Bool("force", false, "Tag with -version-tag even if preflight checks fail (dirty tree, branch behind upstream, ...)")
 
 	flag.Parse()
 
+	logging.SetLogger(logging.New(logging.Options{
+		Verbosity: verbosity(*quietFlag, *verboseFlag, *veryVerboseFlag),
+		Format:    *logFormatFlag,
+	}))
+	git.SetBackend(&git.Runner{Timeout: *gitTimeoutFlag})
+
 	// Detect if running in a non-interactive Git hook environment
 	isNonInteractive := os.Getenv("GIT_POST_COMMIT") != ""
 
 	// Handle non-interactive mode immediately if detected
 	if isNonInteractive {
-		fmt.Println("Running in non-interactive mode...")
+		// Release commits created by git-tagger itself must not trigger another tagging run
+		if git.IsToolCommit() {
+			logging.Logger().Info("Skipping commit created by git-tagger.")
+			return
+		}
+
+		logging.Logger().Info("Running in non-interactive mode...")
 
 		// Verify we're in a Git repository
-		if _, err := os.Stat(".git"); os.IsNotExist(err) {
-			utils.LogAndExit("No Git repository found in the current directory", nil)
+		if _, err := git.GetRepoRoot(); err != nil {
+			fail("No Git repository found in the current directory", err)
 		}
 
 		// Get the currently checked out branch
 		currentBranch, err := git.GetCurrentBranch()
 		if err != nil {
-			utils.LogAndExit("Failed to get the current branch", err)
+			fail("Failed to get the current branch", err)
 		}
 
-		// Ensure that the branch is not empty
-		if currentBranch == "" {
-			utils.LogAndExit("Current branch is not specified or repository is in a detached HEAD state", nil)
+		cfg, err := loadConfig()
+		if err != nil {
+			fail("Failed to load configuration", err)
+		}
+
+		// Branches whose policy forbids tagging are skipped without failing the commit
+		if err := policy.Allow(currentBranch, cfg.Branches, policy.KindBuild); errors.Is(err, policy.ErrNotAllowed) {
+			logging.Logger().Info("Skipping tagging", "error", err)
+			return
+		} else if err != nil {
+			fail("Failed to check the branch policy", err)
+		}
+
+		// The hook tags the commit just created, which uncommitted changes do not affect
+		checks := cfg.Preflight
+		checks.CleanTree = false
+		if !*forceFlag {
+			if err := preflight.Check(currentBranch, checks); err != nil {
+				fail("Refusing to tag", err)
+			}
 		}
 
 		// Update untagged commits for the current branch
-		err = version.UpdateUntaggedCommits(currentBranch)
+		err = version.UpdateUntaggedCommits(currentBranch, cfg)
 		if err != nil {
-			utils.LogAndExit("Failed to update untagged commits", err)
+			fail("Failed to update untagged commits", err)
 		}
 
-		fmt.Println("Version-tagged untagged commits successfully on branch:", currentBranch)
+		logging.Logger().Info("Version-tagged untagged commits successfully", "branch", currentBranch)
 		return
 	}
 
+	// dispatch subcommands (e.g. `tagger undo`) before treating leftover arguments as errors
+	if flag.NArg() > 0 {
+		if cmd, ok := commands[flag.Arg(0)]; ok {
+			if err := cmd.run(flag.Args()[1:]); err != nil {
+				if errors.Is(err, version.ErrNoRelease) {
+					logging.Logger().Info(err.Error())
+					os.Exit(exitNoRelease)
+				}
+				fail(fmt.Sprintf("Command '%s' failed", flag.Arg(0)), err)
+			}
+			return
+		}
+	}
+
 	// handle unparsed flags
 	utils.HandleUnparsedArgs(flag.Args())
 
 	// handle the version tagging logic
 	if *versionTagFlag {
 		branch := *branchFlag
+		// Without -branch, a user at a terminal picks the branch and reviews the tags before they are created
+		interactive := branch == "" && prompt.IsTerminal(os.Stdin) && prompt.IsTerminal(os.Stdout)
+		p := prompt.New(os.Stdin, os.Stdout)
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fail("Failed to load configuration", err)
+		}
 
-		// If branch is not specified via the flag, ask the user to select one
 		if branch == "" {
-			branches, err := git.GetBranches()
-			if err != nil {
-				utils.LogAndExit("Failed to get branches", err)
+			current, err := git.GetCurrentBranch()
+			if err != nil && !(interactive && errors.Is(err, git.ErrDetachedHead)) {
+				fail("Failed to get the current branch (use -branch to choose one)", err)
+			}
+			branch = current
+			if interactive {
+				if branch, err = selectBranch(p, current); err != nil {
+					fail("Failed to select a branch", err)
+				}
 			}
-			if len(branches) == 0 {
-				utils.LogAndExit("No branches found in the repository", nil)
+		}
+
+		if err := policy.Allow(branch, cfg.Branches, policy.KindBuild); err != nil {
+			fail("Refusing to tag", err)
+		}
+		if !*forceFlag {
+			if err := preflight.Check(branch, cfg.Preflight); err != nil {
+				fail("Refusing to tag", err)
 			}
 		}
 
+		if interactive {
+			if err := tagInteractively(p, branch, cfg); err != nil {
+				fail("Failed to tag untagged commits", err)
+			}
+			return
+		}
+
 		// update untagged commits for the selected branch
-		err := version.UpdateUntaggedCommits(branch)
+		err = version.UpdateUntaggedCommits(branch, cfg)
 		if err != nil {
-			utils.LogAndExit("Failed to update untagged commits", err)
+			fail("Failed to update untagged commits", err)
 		}
 
-		fmt.Println("Version-tagged untagged files successfully.")
+		logging.Logger().Info("Version-tagged untagged commits successfully", "branch", branch)
 		os.Exit(0)
 		return
 	}
@@ -88,7 +169,7 @@ func main() {
 		// install the Git hook
 		err := hooks.InstallGitHook(outputPath)
 		if err != nil {
-			utils.LogAndExit("Failed to install Git hook", err)
+			fail("Failed to install Git hook", err)
 		}
 		fmt.Println("Git post-commit hook installed successfully.")
 		return
@@ -98,7 +179,7 @@ func main() {
 	if *uninstallFlag {
 		err := hooks.CleanGitHook()
 		if err != nil {
-			utils.LogAndExit("Failed to uninstall Git hook", err)
+			fail("Failed to uninstall Git hook", err)
 		}
 		fmt.Println("Git post-commit hook uninstalled successfully.")
 		return
@@ -110,5 +191,25 @@ func main() {
 		flag.VisitAll(func(f *flag.Flag) {
 			fmt.Printf("  -%s: %s (default: %s)\n", f.Name, f.Usage, f.DefValue)
 		})
+		printCommands()
+	}
+}
+
+// verbosity converts the verbosity flags into a logging verbosity, the most verbose flag winning.
+// parameters:
+// - quiet: whether -q was given
+// - verbose: whether -v was given
+// - veryVerbose: whether -vv was given
+// returns:
+// - int: -1 for quiet, 0 for the default, 1 for verbose, 2 for very verbose
+func verbosity(quiet, verbose, veryVerbose bool) int {
+	switch {
+	case veryVerbose:
+		return 2
+	case verbose:
+		return 1
+	case quiet:
+		return -1
 	}
+	return 0
 }
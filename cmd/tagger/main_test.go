@@ -1,9 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"git-tagger/internal/config"
 	"git-tagger/internal/git"
 	"git-tagger/internal/testutils"
+	"git-tagger/internal/tui"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -40,3 +45,87 @@ func TestNonInteractiveMode(t *testing.T) {
 func TestVersionTag(t *testing.T) {
 	// Future improvements for version tagging tests
 }
+
+// TestReview validates that the keys of the terminal UI skip commits, change bumps and versions, and export the plan.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestReview(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	if err := testutils.RunGitCommand("tag", "v1.0.0"); err != nil {
+		t.Fatalf("Failed to tag: %v", err)
+	}
+	testutils.CreateAndCommitFile(t, "a.txt", "fix: a")
+	testutils.CreateAndCommitFile(t, "b.txt", "docs: b")
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		t.Fatalf("Failed to get the current branch: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "plan.json")
+	r, err := newReview(branch, output, config.Default())
+	if err != nil {
+		t.Fatalf("newReview failed: %v", err)
+	}
+	press := func(keys ...string) {
+		for _, key := range keys {
+			r.handle(key, 24)
+		}
+	}
+	versions := func() string {
+		var tags []string
+		for _, c := range r.plan.Commits() {
+			if c.Version == nil {
+				tags = append(tags, "skip")
+			} else {
+				tags = append(tags, c.Version.String())
+			}
+		}
+		return strings.Join(tags, " ")
+	}
+
+	if got := versions(); got != "1.0.1 skip" {
+		t.Fatalf("Expected the docs commit to be skipped, got %s", got)
+	}
+	press(tui.KeyDown, " ")
+	if got := versions(); got != "1.0.1 1.0.2" {
+		t.Errorf("Expected the skipped commit to be tagged as a patch, got %s", got)
+	}
+	press("b")
+	if got := versions(); got != "1.0.1 1.1.0" {
+		t.Errorf("Expected the bump to change to minor, got %s", got)
+	}
+	press(tui.KeyUp, "v", tui.KeyBackspace, "9", tui.KeyEnter)
+	if got := versions(); got != "1.0.9 1.1.0" {
+		t.Errorf("Expected the edited version to be used, got %s", got)
+	}
+	press("v", tui.KeyBackspace, tui.KeyBackspace, tui.KeyBackspace, tui.KeyBackspace, tui.KeyBackspace, "1", ".", "0", ".", "0", tui.KeyEnter)
+	if got := versions(); got != "1.0.9 1.1.0" || !strings.Contains(r.message, "not higher than") {
+		t.Errorf("Expected a lower version to be refused, got %s (%q)", got, r.message)
+	}
+	press(" ")
+	if got := versions(); got != "skip 1.1.0" {
+		t.Errorf("Expected the first commit to be skipped, got %s", got)
+	}
+
+	press("x")
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Expected the plan to be exported: %v", err)
+	}
+	var exported exportedPlan
+	if err := json.Unmarshal(data, &exported); err != nil || len(exported.Commits) != 2 || exported.Commits[1].Tag == "" {
+		t.Errorf("Expected the exported plan to list both commits, got %s (%v)", data, err)
+	}
+
+	press("a", "y")
+	if !r.apply || !r.quit {
+		t.Errorf("Expected confirming to apply the plan")
+	}
+	if view := strings.Join(r.view(80, 24), "\n"); !strings.Contains(view, "1 tag(s), 1 skipped") {
+		t.Errorf("Expected the header to summarize the plan, got:\n%s", view)
+	}
+}
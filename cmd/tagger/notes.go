@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/notes"
+	"os"
+)
+
+// runNotes prints the release notes of a version tag.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runNotes(args []string) error {
+	fs := flag.NewFlagSet("notes", flag.ExitOnError)
+	from := fs.String("from", "", "Tag to start from (default: the preceding version tag)")
+	templatePath := fs.String("template", "", "File with a text/template to render instead of the built-in Markdown")
+	output := fs.String("output", "", "Write the notes to this file instead of standard output")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one tag")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	text := ""
+	if *templatePath != "" {
+		content, err := os.ReadFile(*templatePath)
+		if err != nil {
+			return fmt.Errorf("failed to read notes template: %w", err)
+		}
+		text = string(content)
+	}
+
+	n, err := notes.Build(positional[0], *from, cfg)
+	if err != nil {
+		return err
+	}
+	out, err := notes.Render(n, text)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		return os.WriteFile(*output, []byte(out), 0644)
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/git"
+	"git-tagger/internal/version"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runPrune lists and deletes hash-suffixed build tags according to the given retention policy.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runPrune(args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	keepPerMinor := fs.Int("keep-per-minor", 0, "Keep only the newest N build tags of each major.minor line")
+	superseded := fs.Bool("superseded", false, "Prune build tags not newer than the latest release tag")
+	olderThan := fs.String("older-than", "", "Prune build tags older than this age (e.g. 90d, 720h)")
+	remote := fs.String("remote", "", "Also delete the pruned tags from this remote")
+	dryRun := fs.Bool("dry-run", false, "Only list the tags that would be pruned")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	policy := version.PrunePolicy{KeepPerMinor: *keepPerMinor, Superseded: *superseded}
+	if *olderThan != "" {
+		age, err := parseAge(*olderThan)
+		if err != nil {
+			return err
+		}
+		policy.MaxAge = age
+	}
+	if policy.KeepPerMinor <= 0 && !policy.Superseded && policy.MaxAge <= 0 {
+		return fmt.Errorf("no retention policy given: use -keep-per-minor, -superseded or -older-than")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	tags, err := git.GetTagsWithDates()
+	if err != nil {
+		return err
+	}
+
+	candidates, err := version.FindPruneCandidates(tags, policy, cfg, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No build tags to prune.")
+		return nil
+	}
+
+	for _, candidate := range candidates {
+		fmt.Printf("%s  %s  (%s)\n", candidate.Tag, candidate.Date.Format("2006-01-02"), candidate.Reason)
+	}
+
+	if *dryRun {
+		fmt.Printf("%d build tag(s) would be pruned.\n", len(candidates))
+		return nil
+	}
+
+	if err := version.PruneTags(candidates, *remote, cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Pruned %d build tag(s).\n", len(candidates))
+	return nil
+}
+
+// parseAge parses a duration that may additionally use a day suffix (e.g. 90d).
+// parameters:
+// - value: the age to parse
+// returns:
+// - time.Duration: the parsed age
+// - error: an error object if the value is not a valid age, otherwise nil
+func parseAge(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age: %s", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age: %s", value)
+	}
+	return age, nil
+}
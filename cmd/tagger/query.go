@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/version"
+	"strings"
+	"time"
+)
+
+const (
+	formatUsage = "Template for the output, using {{.Major}} {{.Minor}} {{.Patch}} {{.Pre}} {{.Build}} {{.Prefix}} {{.Version}} {{.Tag}}, and {{.Year}} {{.Month}} {{.Week}} {{.Day}} {{.Micro}} for calendar versions"
+)
+
+// runCurrent prints the latest version tag reachable from HEAD.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runCurrent(args []string) error {
+	fs := flag.NewFlagSet("current", flag.ExitOnError)
+	format := fs.String("format", "", formatUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	scheme, err := version.NewScheme(cfg, time.Now)
+	if err != nil {
+		return err
+	}
+
+	tag, current, err := version.CurrentVersion("HEAD", scheme, cfg.Tag.Prefix)
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		fmt.Println(tag)
+		return nil
+	}
+	return printVersion(current, cfg.Tag.Prefix, *format)
+}
+
+// runNext prints the version that tagging the untagged commits would assign to HEAD.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: version.ErrNoRelease if no release is warranted, otherwise an error object if something went wrong
+func runNext(args []string) error {
+	fs := flag.NewFlagSet("next", flag.ExitOnError)
+	format := fs.String("format", "{{.Tag}}", formatUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	plan, err := version.PlanUntaggedCommits("HEAD", cfg)
+	if err != nil {
+		return err
+	}
+
+	next, err := plan.Next()
+	if err != nil {
+		return err
+	}
+	return printVersion(next, cfg.Tag.Prefix, *format)
+}
+
+// printVersion prints a version rendered through the given template.
+func printVersion(v version.Version, prefix, format string) error {
+	out, err := version.FormatVersion(v, prefix, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"git-tagger/internal/version"
+)
+
+// runBump tags HEAD with the latest version bumped by an explicit level.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runBump(args []string) error {
+	fs := flag.NewFlagSet("bump", flag.ExitOnError)
+	force := fs.Bool("force", false, "Replace an existing tag and allow versions lower than the latest")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one level: major, minor, patch or prerelease")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	next, err := version.BumpVersion(positional[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	tag, err := version.TagRelease(next, "HEAD", cfg, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tagged HEAD with %s\n", tag)
+	return nil
+}
+
+// runSet tags HEAD with an explicitly given version.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runSet(args []string) error {
+	fs := flag.NewFlagSet("set", flag.ExitOnError)
+	force := fs.Bool("force", false, "Replace an existing tag and allow versions lower than the latest")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one version")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	next, err := version.ParseVersionArg(positional[0], cfg.Tag.Prefix)
+	if err != nil {
+		return err
+	}
+
+	tag, err := version.TagRelease(next, "HEAD", cfg, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tagged HEAD with %s\n", tag)
+	return nil
+}
+
+// runPromote ends initial development by tagging HEAD with 1.0.0 (or another major release).
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runPromote(args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("expected at most one version")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	target := ""
+	if len(positional) == 1 {
+		target = positional[0]
+	}
+	next, err := version.PromoteVersion(target, cfg.Tag.Prefix)
+	if err != nil {
+		return err
+	}
+
+	tag, err := version.TagRelease(next, "HEAD", cfg, false)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tagged HEAD with %s\n", tag)
+	return nil
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/logging"
+	"git-tagger/internal/policy"
+	"git-tagger/internal/preflight"
+	"git-tagger/internal/prompt"
+	"git-tagger/internal/tui"
+	"git-tagger/internal/version"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Screens of the terminal UI.
+const (
+	screenList    = iota // the untagged commits
+	screenDiff           // the diff of the selected commit
+	screenVersion        // editing the version of the selected commit
+	screenConfirm        // confirming to create the tags
+)
+
+// review is the state of the terminal UI reviewing the plan of a branch.
+type review struct {
+	cfg       *config.Config
+	branch    string
+	scheme    version.Scheme
+	output    string // file the plan is exported to
+	plan      *version.Plan
+	overrides map[string]version.Override
+
+	screen  int
+	cursor  int      // selected commit
+	top     int      // first visible line of the list or diff
+	input   string   // version being typed
+	diff    []string // lines of the diff being viewed
+	message string   // result of the last action, shown in the status line
+	apply   bool     // whether the user confirmed creating the tags
+	quit    bool     // whether the terminal UI is done
+}
+
+// exportedPlan is the JSON form of a plan written by the export action.
+type exportedPlan struct {
+	Branch  string           `json:"branch"`
+	Base    string           `json:"base"`
+	Commits []exportedCommit `json:"commits"`
+}
+
+// exportedCommit is an untagged commit of an exported plan.
+type exportedCommit struct {
+	Commit  string   `json:"commit"`
+	Subject string   `json:"subject"`
+	Level   string   `json:"level"`
+	Reason  string   `json:"reason"`
+	Version string   `json:"version,omitempty"`
+	Tag     string   `json:"tag,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+}
+
+// runTUI reviews the untagged commits of a branch in a full-screen terminal view and creates their tags.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	branch := fs.String("branch", "", "Branch to tag (default: the current branch)")
+	output := fs.String("output", "tagger-plan.json", "File the plan is exported to")
+	force := fs.Bool("force", false, "Tag even if preflight checks fail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if !prompt.IsTerminal(os.Stdin) || !prompt.IsTerminal(os.Stdout) {
+		return fmt.Errorf("tui needs a terminal; use -version-tag in scripts")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *branch == "" {
+		if *branch, err = git.GetCurrentBranch(); err != nil {
+			return fmt.Errorf("%w (use -branch to choose one)", err)
+		}
+	}
+	if err := policy.Allow(*branch, cfg.Branches, policy.KindBuild); err != nil {
+		return err
+	}
+	if !*force {
+		if err := preflight.Check(*branch, cfg.Preflight); err != nil {
+			return err
+		}
+	}
+
+	r, err := newReview(*branch, *output, cfg)
+	if err != nil {
+		return err
+	}
+	for {
+		if err := r.run(); err != nil || !r.apply {
+			return err
+		}
+		runID, err := applyPreviewed(r.plan, r.overrides, cfg)
+		if err != nil {
+			return err
+		}
+		if runID != "" {
+			fmt.Printf("Created %d tag(s) (run %s; revert with `tagger undo`).\n", len(r.plan.Tags), runID)
+			return nil
+		}
+		// another run tagged commits while the user reviewed the plan
+		r.apply, r.quit = false, false
+		r.message = "The plan changed while waiting for another tagging run; review it again."
+		if err := r.replan(); err != nil {
+			return err
+		}
+	}
+}
+
+// newReview computes the plan of a branch for reviewing it.
+// parameters:
+// - branch: the branch to tag
+// - output: the file the plan is exported to
+// - cfg: the repository configuration
+// returns:
+// - *review: the review
+// - error: an error object if something went wrong, otherwise nil
+func newReview(branch, output string, cfg *config.Config) (*review, error) {
+	scheme, err := version.NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	r := &review{cfg: cfg, branch: branch, scheme: scheme, output: output, overrides: map[string]version.Override{}}
+	return r, r.replan()
+}
+
+// run shows the review in the terminal until the user quits or confirms the tags. Log messages are
+// discarded meanwhile, as they would garble the screen.
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (r *review) run() (err error) {
+	if len(r.plan.Commits()) == 0 {
+		fmt.Printf("No untagged commits found on %s.\n", r.branch)
+		return nil
+	}
+
+	t, err := tui.Open(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	logger := logging.Logger()
+	logging.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer func() {
+		logging.SetLogger(logger)
+		err = errors.Join(err, t.Close())
+	}()
+
+	for !r.quit {
+		width, height := t.Size()
+		t.Draw(r.view(width, height))
+		key, err := t.ReadKey()
+		if err != nil {
+			return err
+		}
+		r.handle(key, height)
+	}
+	return nil
+}
+
+// replan recomputes the plan with the current overrides.
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (r *review) replan() error {
+	plan, err := version.PlanWithOverrides(r.branch, r.cfg, r.overrides)
+	if err != nil {
+		return err
+	}
+	r.plan = plan
+	r.cursor = min(r.cursor, max(0, len(plan.Commits())-1))
+	return nil
+}
+
+// override changes the override of a commit and recomputes the plan, keeping the previous override if
+// the plan cannot be computed with the new one.
+// parameters:
+// - commit: the commit hash
+// - o: the new override; the zero Override removes it
+func (r *review) override(commit string, o version.Override) {
+	previous, had := r.overrides[commit]
+	if o.Level == "" && o.Version == nil {
+		delete(r.overrides, commit)
+	} else {
+		r.overrides[commit] = o
+	}
+	if err := r.replan(); err != nil {
+		if had {
+			r.overrides[commit] = previous
+		} else {
+			delete(r.overrides, commit)
+		}
+		r.message = err.Error()
+	}
+}
+
+// handle updates the review for a key press.
+// parameters:
+// - key: the key, as returned by tui.Terminal.ReadKey
+// - height: the number of rows of the terminal
+func (r *review) handle(key string, height int) {
+	r.message = ""
+	switch r.screen {
+	case screenDiff:
+		r.handleDiff(key, height)
+	case screenVersion:
+		r.handleVersion(key)
+	case screenConfirm:
+		if key == "y" {
+			r.apply, r.quit = true, true
+		}
+		r.screen = screenList
+	default:
+		r.handleList(key, height)
+	}
+}
+
+// handleList handles a key press on the list of commits.
+func (r *review) handleList(key string, height int) {
+	commits := r.plan.Commits()
+	selected := commits[r.cursor]
+	switch key {
+	case tui.KeyUp, "k":
+		r.cursor = max(0, r.cursor-1)
+	case tui.KeyDown, "j":
+		r.cursor = min(len(commits)-1, r.cursor+1)
+	case tui.KeyPageUp:
+		r.cursor = max(0, r.cursor-listHeight(height))
+	case tui.KeyPageDown:
+		r.cursor = min(len(commits)-1, r.cursor+listHeight(height))
+	case tui.KeyHome:
+		r.cursor = 0
+	case tui.KeyEnd:
+		r.cursor = len(commits) - 1
+	case " ":
+		// unskipping restores the detected bump, or tags the commit as a patch if none was detected
+		if selected.Version != nil {
+			r.override(selected.Commit, version.Override{Level: "none"})
+			return
+		}
+		r.override(selected.Commit, version.Override{})
+		if r.find(selected.Commit).Version == nil {
+			r.override(selected.Commit, version.Override{Level: "patch"})
+		}
+	case "b":
+		next := map[string]string{"none": "patch", "patch": "minor", "minor": "major", "major": "none"}[selected.Level]
+		if next == "" {
+			next = "patch"
+		}
+		r.override(selected.Commit, version.Override{Level: next})
+	case "v":
+		r.screen, r.input = screenVersion, ""
+		if selected.Version != nil {
+			r.input = selected.Version.String()
+		}
+	case "r":
+		r.override(selected.Commit, version.Override{})
+	case "d", tui.KeyEnter:
+		out, err := git.ShowCommit(selected.Commit)
+		if err != nil {
+			r.message = err.Error()
+			return
+		}
+		r.screen, r.top, r.diff = screenDiff, 0, strings.Split(strings.TrimRight(out, "\n"), "\n")
+	case "x":
+		if err := r.export(); err != nil {
+			r.message = err.Error()
+		} else {
+			r.message = "Exported the plan to " + r.output
+		}
+	case "a":
+		if len(r.plan.Tags) == 0 {
+			r.message = "No commit warrants a release; change a bump first."
+			return
+		}
+		r.screen = screenConfirm
+	case "q", tui.KeyEscape, tui.KeyCtrlC:
+		r.quit = true
+	}
+}
+
+// handleDiff handles a key press while viewing a diff.
+func (r *review) handleDiff(key string, height int) {
+	last := max(0, len(r.diff)-(height-2))
+	switch key {
+	case tui.KeyUp, "k":
+		r.top = max(0, r.top-1)
+	case tui.KeyDown, "j", tui.KeyEnter:
+		r.top = min(last, r.top+1)
+	case tui.KeyPageUp, "b":
+		r.top = max(0, r.top-(height-2))
+	case tui.KeyPageDown, " ":
+		r.top = min(last, r.top+(height-2))
+	case tui.KeyHome, "g":
+		r.top = 0
+	case tui.KeyEnd, "G":
+		r.top = last
+	case "q", tui.KeyEscape, tui.KeyCtrlC:
+		r.screen = screenList
+	}
+}
+
+// handleVersion handles a key press while editing the version of the selected commit.
+func (r *review) handleVersion(key string) {
+	switch key {
+	case tui.KeyEnter:
+		r.screen = screenList
+		selected := r.plan.Commits()[r.cursor]
+		if r.input == "" {
+			r.override(selected.Commit, version.Override{})
+			return
+		}
+		v, err := r.scheme.Parse(strings.TrimPrefix(r.input, r.cfg.Tag.Prefix))
+		if err != nil {
+			r.message = err.Error()
+			return
+		}
+		r.override(selected.Commit, version.Override{Version: v})
+	case tui.KeyEscape, tui.KeyCtrlC:
+		r.screen = screenList
+	case tui.KeyBackspace:
+		r.input = r.input[:max(0, len(r.input)-1)]
+	default:
+		if len(key) == 1 && key[0] > ' ' && key[0] < 0x7f {
+			r.input += key
+		}
+	}
+}
+
+// view renders the screen.
+// parameters:
+// - width: the number of columns of the terminal
+// - height: the number of rows of the terminal
+// returns:
+// - []string: the lines of the screen
+func (r *review) view(width, height int) []string {
+	if r.screen == screenDiff {
+		selected := r.plan.Commits()[r.cursor]
+		lines := []string{tui.Bold + fmt.Sprintf("%s %s", shortHash(selected.Commit), selected.Message) + tui.Reset}
+		lines = append(lines, r.diff[min(r.top, len(r.diff)):min(r.top+height-2, len(r.diff))]...)
+		for len(lines) < height-1 {
+			lines = append(lines, "~")
+		}
+		return append(lines, tui.Reverse+fmt.Sprintf(" line %d/%d  ↑/↓ scroll  space/b page  q back ", r.top+1, len(r.diff))+strings.Repeat(" ", width))
+	}
+
+	base := r.plan.Base
+	if base == "" {
+		base = "no version tags"
+	}
+	lines := []string{tui.Bold + fmt.Sprintf("git-tagger: %s from %s, %d tag(s), %d skipped", r.plan.Branch, base, len(r.plan.Tags), len(r.plan.Skipped)) + tui.Reset, ""}
+
+	commits := r.plan.Commits()
+	rows := listHeight(height)
+	if r.cursor < r.top {
+		r.top = r.cursor
+	} else if r.cursor >= r.top+rows {
+		r.top = r.cursor - rows + 1
+	}
+	for i := r.top; i < min(len(commits), r.top+rows); i++ {
+		c := commits[i]
+		tag := "skip"
+		if c.Version != nil {
+			tag = c.Version.Tag(r.cfg.Tag.Prefix)
+		}
+		line := fmt.Sprintf(" %s  %-5s  %-14s  %s", shortHash(c.Commit), c.Level, tag, c.Message)
+		if i == r.cursor {
+			line = tui.Reverse + line + strings.Repeat(" ", width) + tui.Reset
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < rows+2 {
+		lines = append(lines, "")
+	}
+
+	selected := commits[r.cursor]
+	lines = append(lines, "", "Reason: "+selected.Reason)
+	if len(selected.Issues) > 0 {
+		lines = append(lines, "Issues: "+strings.Join(selected.Issues, ", "))
+	} else {
+		lines = append(lines, "")
+	}
+
+	status := r.message
+	switch r.screen {
+	case screenVersion:
+		status = fmt.Sprintf("Version for %s (empty to reset, esc to cancel): %s_", shortHash(selected.Commit), r.input)
+	case screenConfirm:
+		status = fmt.Sprintf("Create %d tag(s)? (y/n)", len(r.plan.Tags))
+	case screenList:
+		if status == "" {
+			status = "↑/↓ move  space skip  b bump  v version  r reset  d diff  x export  a apply  q quit"
+		}
+	}
+	return append(lines, tui.Reverse+" "+status+strings.Repeat(" ", width)+tui.Reset)
+}
+
+// find returns the planned or skipped commit with the given hash.
+func (r *review) find(commit string) version.PlannedTag {
+	for _, c := range r.plan.Commits() {
+		if c.Commit == commit {
+			return c
+		}
+	}
+	return version.PlannedTag{}
+}
+
+// export writes the plan as JSON to the output file.
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (r *review) export() error {
+	exported := exportedPlan{Branch: r.plan.Branch, Base: r.plan.Base, Commits: []exportedCommit{}}
+	for _, c := range r.plan.Commits() {
+		e := exportedCommit{Commit: c.Commit, Subject: c.Message, Level: c.Level, Reason: c.Reason, Tag: c.Tag, Issues: c.Issues}
+		if c.Version != nil {
+			e.Version = c.Version.String()
+		}
+		exported.Commits = append(exported.Commits, e)
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the plan: %w", err)
+	}
+	if err := os.WriteFile(r.output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to export the plan: %w", err)
+	}
+	return nil
+}
+
+// listHeight returns the number of commits shown at once, leaving room for the header, details and status line.
+func listHeight(height int) int {
+	return max(1, height-6)
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(commit string) string {
+	return commit[:min(7, len(commit))]
+}
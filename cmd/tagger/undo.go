@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"git-tagger/internal/journal"
+	"git-tagger/internal/lock"
+	"git-tagger/internal/logging"
+)
+
+// runUndo deletes the tags created by a tagging run, locally and optionally on a remote.
+// parameters:
+// - args: the command-line arguments following the command name
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func runUndo(args []string) (err error) {
+	fs := flag.NewFlagSet("undo", flag.ExitOnError)
+	remote := fs.String("remote", "", "Also delete the tags from this remote (default: git.remote_name if git.push_tags is set)")
+	list := fs.Bool("list", false, "List recorded tagging runs instead of undoing one")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		runs, err := journal.List()
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			fmt.Println("No tagging runs recorded.")
+			return nil
+		}
+		for _, run := range runs {
+			fmt.Printf("%s  %-11s  %-20s  %d tag(s)\n", run.RunID, run.Status, run.Branch, len(run.Tags))
+		}
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *remote == "" && cfg.Git.PushTags {
+		*remote = cfg.Git.RemoteName
+	}
+	l, err := lock.Acquire(context.Background(), cfg.Lock)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, l.Release()) }()
+
+	var run *journal.Journal
+	switch len(positional) {
+	case 0:
+		run, err = journal.Latest()
+	case 1:
+		run, err = journal.Load(positional[0])
+	default:
+		return fmt.Errorf("expected at most one run ID, got %d", len(positional))
+	}
+	if err != nil {
+		return err
+	}
+
+	if run.Status == journal.StatusUndone {
+		return fmt.Errorf("run %s has already been undone", run.RunID)
+	}
+
+	restored := 0
+	for i := len(run.Tags) - 1; i >= 0; i-- {
+		if run.Tags[i].Replaced != "" {
+			logging.Logger().Info("Restoring replaced tag", "tag", run.Tags[i].Tag)
+			restored++
+			continue
+		}
+		logging.Logger().Info("Deleting tag", "tag", run.Tags[i].Tag)
+	}
+	for i := len(run.Commits) - 1; i >= 0; i-- {
+		logging.Logger().Info("Resetting release commit", "commit", run.Commits[i].Commit)
+	}
+	if err := run.Rollback(*remote); err != nil {
+		return err
+	}
+	if err := run.Finish(journal.StatusUndone); err != nil {
+		return err
+	}
+
+	fmt.Printf("Undid run %s (%d tag(s) deleted, %d restored).\n", run.RunID, len(run.Tags)-restored, restored)
+	return nil
+}
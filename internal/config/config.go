@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/git"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FileName = "config.yaml" // name of the configuration file in the repository root
+)
+
+// Config holds the git-tagger settings of a repository.
+type Config struct {
+	Tag        TagConfig        `yaml:"tag"`
+	Git        GitConfig        `yaml:"git"`
+	Commits    CommitsConfig    `yaml:"commits"`
+	History    HistoryConfig    `yaml:"history"`
+	Versioning VersioningConfig `yaml:"versioning"`
+	Release    ReleaseConfig    `yaml:"release"`
+	Issues     IssuesConfig     `yaml:"issues"`
+	Lock       LockConfig       `yaml:"lock"`
+	Preflight  PreflightConfig  `yaml:"preflight"`
+	Branches   BranchesConfig   `yaml:"branches"`
+}
+
+// TagConfig controls how version tags are named and created.
+type TagConfig struct {
+	Prefix         string `yaml:"prefix"`          // prefix of version tags (e.g. "v")
+	Message        string `yaml:"message"`         // annotation of automated tags, a template with the version fields and .Commit
+	IncrementLevel string `yaml:"increment_level"` // bump level for commits that match no rule
+	Sign           bool   `yaml:"sign"`            // create GPG-signed tags
+	PrereleaseID   string `yaml:"prerelease_id"`   // identifier of pre-release versions (e.g. "rc")
+}
+
+// GitConfig controls interaction with remotes.
+type GitConfig struct {
+	PushTags   bool   `yaml:"push_tags"`   // push the tags of every tagging run to the remote, and undo them there
+	RemoteName string `yaml:"remote_name"` // remote to push tags to
+}
+
+// CommitsConfig controls how commits are classified into version increments.
+type CommitsConfig struct {
+	Rules           []Rule   `yaml:"rules"`            // custom rules, evaluated in order before the built-in rules
+	DisableDefaults bool     `yaml:"disable_defaults"` // do not fall back to the built-in Conventional Commits rules
+	SkipTypes       []string `yaml:"skip_types"`       // Conventional Commits types that never warrant a release
+	SkipMarkers     []string `yaml:"skip_markers"`     // markers anywhere in a message that exclude the commit from releases
+}
+
+// HistoryConfig controls how the commit history of a branch is walked.
+type HistoryConfig struct {
+	FirstParent  bool `yaml:"first_parent"`  // only walk the first-parent (mainline) history
+	MergeAsUnit  bool `yaml:"merge_as_unit"` // version a merge commit once, with the highest bump of its merged commits (implies first_parent)
+	SquashTitles bool `yaml:"squash_titles"` // classify squash and pull request merges by their pull request title
+}
+
+// VersioningConfig controls how versions are incremented.
+type VersioningConfig struct {
+	Scheme       string `yaml:"scheme"`        // versioning scheme: semver or calver
+	CalVerFormat string `yaml:"calver_format"` // format of calendar versions, e.g. YYYY.0M.MICRO
+	ZeroPolicy   string `yaml:"zero_policy"`   // bumps while the major version is 0: breaking-minor, feat-patch or strict
+}
+
+// ReleaseConfig controls the files updated when releasing a version.
+type ReleaseConfig struct {
+	Files         []VersionFile `yaml:"files"`          // files whose version strings are rewritten on release
+	Paths         []string      `yaml:"paths"`          // further files to include in the release commit, e.g. regenerated by the build
+	Commit        bool          `yaml:"commit"`         // commit updated version files as a release commit and tag that commit
+	CommitMessage string        `yaml:"commit_message"` // template of the release commit message, e.g. "chore(release): {{.Tag}}"
+	Author        Identity      `yaml:"author"`         // author of release commits (default: git configuration)
+	Committer     Identity      `yaml:"committer"`      // committer of release commits (default: git configuration)
+	OnVersionTag  bool          `yaml:"on_version_tag"` // also create a release commit when tagging untagged commits
+}
+
+// Identity is the name and e-mail address used for commits created by git-tagger.
+type Identity struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+}
+
+// VersionFile is a file containing a version string that is rewritten on release.
+type VersionFile struct {
+	Path    string `yaml:"path"`    // path relative to the repository root
+	Type    string `yaml:"type"`    // format of the file: text, json, yaml or regex (default: text)
+	Key     string `yaml:"key"`     // dotted path of the version value in json and yaml files (default: version)
+	Pattern string `yaml:"pattern"` // regular expression for regex files; its first group (or the whole match) is replaced
+	Format  string `yaml:"format"`  // template of the written value (default: {{.Version}})
+}
+
+// IssuesConfig controls how issue references are found in commit messages.
+type IssuesConfig struct {
+	Patterns        []string `yaml:"patterns"`         // extra regular expressions; the first group (or whole match) is the issue
+	DisableDefaults bool     `yaml:"disable_defaults"` // do not match #123 references and the PROJ-123 references of the projects
+	Projects        []string `yaml:"projects"`         // project keys of PROJ-123 style references, which are only matched for these projects
+}
+
+// LockConfig controls the repository lock held by tagging runs, so that concurrent runs do not assign the same versions.
+type LockConfig struct {
+	Wait    bool          `yaml:"wait"`    // wait for a running tagging run to finish instead of failing immediately
+	Timeout time.Duration `yaml:"timeout"` // maximum time to wait for the lock, e.g. 30s
+	Retries int           `yaml:"retries"` // times a run recomputes its versions when a conflicting tag appears
+}
+
+// PreflightConfig controls the checks run before tagging untagged commits; -force skips them.
+type PreflightConfig struct {
+	CleanTree   bool `yaml:"clean_tree"`   // require no uncommitted changes to tracked files
+	UpToDate    bool `yaml:"up_to_date"`   // require the branch not to be behind its upstream, as of the last fetch
+	NoOperation bool `yaml:"no_operation"` // require no merge, rebase, cherry-pick, revert or bisect in progress
+}
+
+// BranchesConfig assigns tagging policies to branches by glob pattern. Lists are matched in the order
+// forbidden, release, prerelease; branches matching none get the default policy. Maintenance branches
+// additionally keep their versions within the version line in their name.
+type BranchesConfig struct {
+	Release          []string `yaml:"release"`           // branches that may create release, pre-release and build tags, e.g. main
+	Prerelease       []string `yaml:"prerelease"`        // branches that may only create pre-release and build tags, e.g. develop
+	Forbidden        []string `yaml:"forbidden"`         // branches that may not be tagged at all, e.g. wip/*
+	Default          string   `yaml:"default"`           // policy of other branches and detached HEADs: release, prerelease or forbidden
+	Maintenance      []string `yaml:"maintenance"`       // branches named after a version line, e.g. release/1.x or release/2.3.x
+	MaintenanceBumps string   `yaml:"maintenance_bumps"` // commits warranting a bump that leaves the line: cap or reject
+}
+
+// Rule maps commits whose message matches a regular expression to a version increment level.
+type Rule struct {
+	Name    string `yaml:"name"`    // name shown when explaining a classification
+	Field   string `yaml:"field"`   // part of the message to match: subject, body, trailers or message (default: subject)
+	Pattern string `yaml:"pattern"` // regular expression to match
+	Level   string `yaml:"level"`   // resulting level: major, minor, patch or none
+}
+
+// ---------- Config Functions ----------
+
+// Default returns the configuration used when no configuration file exists.
+// returns:
+// - *Config: the default configuration
+func Default() *Config {
+	return &Config{
+		Tag: TagConfig{
+			Prefix:         "v",
+			Message:        "Automated tagging for commit {{.Commit}}",
+			IncrementLevel: "patch",
+			PrereleaseID:   "rc",
+		},
+		Git: GitConfig{
+			RemoteName: "origin",
+		},
+		Versioning: VersioningConfig{
+			Scheme:       "semver",
+			CalVerFormat: "YYYY.0M.MICRO",
+			ZeroPolicy:   "breaking-minor",
+		},
+		Release: ReleaseConfig{
+			Commit:        true,
+			CommitMessage: "chore(release): {{.Tag}}",
+		},
+		Lock: LockConfig{
+			Wait:    true,
+			Timeout: 30 * time.Second,
+			Retries: 2,
+		},
+		Branches: BranchesConfig{
+			Default:          "release",
+			MaintenanceBumps: "cap",
+		},
+		Preflight: PreflightConfig{
+			CleanTree:   true,
+			UpToDate:    true,
+			NoOperation: true,
+		},
+		Commits: CommitsConfig{
+			SkipTypes:   []string{"docs", "chore", "ci", "test", "style"},
+			SkipMarkers: []string{"[skip release]", "[release skip]", "[no release]"},
+		},
+	}
+}
+
+// Load reads a configuration file, filling unset values with defaults.
+// A missing file is not an error; the defaults are returned instead.
+// parameters:
+// - path: the path to the configuration file
+// returns:
+// - *Config: the loaded configuration
+// - error: an error object if something went wrong, otherwise nil
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadFromRepo reads the configuration file from the root of the current repository.
+// returns:
+// - *Config: the loaded configuration
+// - error: an error object if something went wrong, otherwise nil
+func LoadFromRepo() (*Config, error) {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	return Load(filepath.Join(root, FileName))
+}
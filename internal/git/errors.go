@@ -0,0 +1,50 @@
+package git
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors reported by git operations, checkable with errors.Is.
+var (
+	ErrNotARepo     = errors.New("not a git repository")
+	ErrNoTags       = errors.New("no version tags found")
+	ErrDetachedHead = errors.New("detached HEAD: not on any branch")
+	ErrDirtyTree    = errors.New("working tree has uncommitted changes")
+	ErrTagExists    = errors.New("tag already exists")
+)
+
+// CommandError is returned when a git command fails, holding the command and the error output of git.
+// It matches ErrNotARepo and ErrTagExists with errors.Is when git reported those conditions, and
+// context.Canceled or context.DeadlineExceeded when the command was cancelled or timed out.
+type CommandError struct {
+	Args     []string // arguments of the git command, without "git"
+	ExitCode int      // exit code of git, or -1 if it could not be run
+	Stderr   string   // trimmed error output of git
+	Err      error    // the error returned by running the command
+}
+
+// Error returns the command, its error and the error output of git.
+func (e *CommandError) Error() string {
+	msg := "git " + quoteArgs(e.Args) + ": " + e.Err.Error()
+	if e.Stderr == "" {
+		return msg
+	}
+	return msg + ": " + e.Stderr
+}
+
+// Unwrap returns the error returned by running the command.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error output of git describes the condition of a sentinel error.
+func (e *CommandError) Is(target error) bool {
+	switch target {
+	case ErrNotARepo:
+		return strings.Contains(e.Stderr, "not a git repository")
+	case ErrTagExists:
+		return len(e.Args) > 0 && e.Args[0] == "tag" && strings.Contains(e.Stderr, "already exists")
+	}
+	return false
+}
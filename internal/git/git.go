@@ -1,16 +1,67 @@
 package git
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"git-tagger/internal/utils"
-	"os/exec"
-	"sort"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const (
+	tagBatchSize = 100 // maximum number of tags passed to a single git invocation
+
+	commitFormat = "%H%x00%h%x00%an%x00%ae%x00%ct%x00%s%x00%b%x00%(trailers:only,unfold)%x00%P" // NUL-separated hashes, author, date, subject, body, trailers and parents
+	recordSep    = "\x1e"                                                                       // separates the commits of batched queries
+
+	ToolCommitEnv = "GIT_TAGGER_COMMIT" // set for hooks run by commits that git-tagger creates itself
+)
+
+// Commit holds the parts of a commit message used to classify and describe commits.
+type Commit struct {
+	Hash        string
+	ShortHash   string // abbreviated hash, as git rev-parse --short prints it
+	Author      string
+	AuthorEmail string
+	Date        time.Time // committer date
+	Subject     string
+	Body        string   // message body, including any trailers
+	Trailers    []string // trailer lines such as "Refs: PROJ-123"
+	Parents     []string // parent hashes, first parent first; nil if unknown
+}
+
+// Identity is the name and e-mail address of a commit author or committer.
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// CommitOptions controls how commits are created.
+type CommitOptions struct {
+	Paths     []string // paths to stage and commit
+	Author    Identity // author of the commit; empty fields fall back to the git configuration
+	Committer Identity // committer of the commit; empty fields fall back to the git configuration
+}
+
+// TagInfo describes a tag, when it was created and the commit it points to.
+type TagInfo struct {
+	Name   string
+	Date   time.Time
+	Commit string
+}
+
 // ---------- Tagging Functions ----------
 
+// TagOptions controls how a tag is created.
+type TagOptions struct {
+	Sign  bool      // create a GPG-signed tag instead of a plain annotated tag
+	Force bool      // replace an existing tag with the same name
+	Date  time.Time // date of the tag, e.g. of the tagged commit when tagging history; zero for now
+}
+
 // CreateTag creates an annotated Git tag with the given tag name, message, and commit.
 // Parameters:
 // - tag: The name of the tag to create
@@ -19,84 +70,249 @@ import (
 // Returns:
 // - error: An error object if something went wrong, otherwise nil
 func CreateTag(tag, message, commit string) error {
-	return runGitCommandVoid("tag", "-a", tag, "-m", message, commit)
+	return CreateTagWithOptions(tag, message, commit, TagOptions{})
 }
 
-// FindUntagged finds true untagged commits in a given branch.
+// CreateTagWithOptions creates an annotated or signed Git tag with the given tag name, message, and commit.
+// Parameters:
+// - tag: The name of the tag to create
+// - message: The message to annotate the tag with
+// - commit: The commit hash to tag
+// - opts: Signing and overwrite options
+// Returns:
+// - error: An error object if something went wrong, otherwise nil
+func CreateTagWithOptions(tag, message, commit string, opts TagOptions) error {
+	args := []string{"tag", "-a"}
+	if opts.Sign {
+		args = []string{"tag", "-s"}
+	}
+	if opts.Force {
+		args = append(args, "-f")
+	}
+	args = append(args, tag, "-m", message, commit)
+	if !opts.Date.IsZero() {
+		// git takes the date of annotated tags from the committer date
+		_, err := runWithEnv([]string{"GIT_COMMITTER_DATE=" + opts.Date.Format(time.RFC3339)}, args...)
+		return err
+	}
+	return runGitCommandVoid(args...)
+}
+
+// DeleteTags deletes one or more local Git tags.
 // parameters:
-// - branch: the branch from which to find untagged commits
+// - tags: the names of the tags to delete
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func DeleteTags(tags ...string) error {
+	for _, batch := range batchStrings(tags, tagBatchSize) {
+		if err := runGitCommandVoid(append([]string{"tag", "-d"}, batch...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRemoteTags deletes one or more tags from the given remote. Tags the remote does not have, e.g.
+// because they were never pushed or are already deleted, are skipped.
+// parameters:
+// - remote: the name of the remote (e.g. origin)
+// - tags: the names of the tags to delete
 // returns:
-// - []string: a slice of commit hashes that are untagged
 // - error: an error object if something went wrong, otherwise nil
-func FindUntagged(branch string) ([]string, error) {
-	// get all commits on the branch
-	commits, err := RunGitCommand("rev-list", "--reverse", branch)
+func DeleteRemoteTags(remote string, tags ...string) error {
+	existing, err := ListRemoteTags(remote)
 	if err != nil {
-		fmt.Printf("error retrieving commits for branch '%s': %v\n", branch, err)
-		return nil, utils.WrapErrorf("failed to find untagged commits: %w", err)
+		return err
 	}
+	tags = slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return !slices.Contains(existing, tag) })
 
-	// filter out commits that already have tags
-	var untaggedCommits []string
-	for _, commit := range commits {
-		tags, err := GetTagsForCommit(commit)
-		if err != nil {
-			return nil, err
+	for _, batch := range batchStrings(tags, tagBatchSize) {
+		refspecs := make([]string, len(batch))
+		for i, tag := range batch {
+			refspecs[i] = ":refs/tags/" + tag
 		}
-		if len(tags) == 0 {
-			untaggedCommits = append(untaggedCommits, commit)
+		if err := runGitCommandVoid(append([]string{"push", remote}, refspecs...)...); err != nil {
+			return err
 		}
 	}
-
-	return untaggedCommits, nil
+	return nil
 }
 
-// GetLatestTag retrieves the latest semantic version tag from the git repository.
-// Returns:
-// - string: The latest tag as a string
-// - error: An error object if something went wrong or if no semantic version tags were found
-func GetLatestTag() (string, error) {
-	// Get all tags
-	tags, err := RunGitCommand("tag")
+// ListRemoteTags lists the tags of the given remote.
+// parameters:
+// - remote: the name of the remote (e.g. origin)
+// returns:
+// - []string: the names of the tags on the remote
+// - error: an error object if something went wrong, otherwise nil
+func ListRemoteTags(remote string) ([]string, error) {
+	lines, err := RunGitCommand("ls-remote", "--tags", "--refs", remote)
 	if err != nil {
-		return "", utils.WrapErrorf("failed to retrieve tags: %w", err)
+		return nil, fmt.Errorf("failed to list tags of %s: %w", remote, err)
+	}
+	tags := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if _, ref, ok := strings.Cut(line, "\t"); ok {
+			tags = append(tags, strings.TrimPrefix(ref, "refs/tags/"))
+		}
 	}
+	return tags, nil
+}
 
-	// filter tags that match the semantic versioning format
-	var semVerTags []string
-	for _, tag := range tags {
-		if utils.IsSemVer(tag) {
-			semVerTags = append(semVerTags, tag)
+// PushTags pushes one or more local tags to the given remote atomically, so that either all tags of a
+// batch are pushed or none is.
+// parameters:
+// - remote: the name of the remote (e.g. origin)
+// - force: whether to replace tags of the same name on the remote
+// - tags: the names of the tags to push
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func PushTags(remote string, force bool, tags ...string) error {
+	for _, batch := range batchStrings(tags, tagBatchSize) {
+		args := []string{"push", "--atomic", remote}
+		for _, tag := range batch {
+			refspec := "refs/tags/" + tag + ":refs/tags/" + tag
+			if force {
+				refspec = "+" + refspec
+			}
+			args = append(args, refspec)
+		}
+		if err := runGitCommandVoid(args...); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// GetTagObject retrieves the object a tag points to: the tag object of annotated tags, the commit of
+// lightweight tags.
+// parameters:
+// - tag: the name of the tag
+// returns:
+// - string: the full hash of the object
+// - error: an error object if the tag does not exist or something went wrong, otherwise nil
+func GetTagObject(tag string) (string, error) {
+	out, err := run("rev-parse", "--verify", "refs/tags/"+tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tag %s: %w", tag, err)
+	}
+	return strings.TrimSpace(out), nil
+}
 
-	if len(semVerTags) == 0 {
-		return "", utils.WrapErrorf("no semantic version tags found", err)
+// RestoreTag points a tag back to an object retrieved with GetTagObject, e.g. after the tag was replaced.
+// parameters:
+// - tag: the name of the tag
+// - object: the full hash of the tag object or commit
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func RestoreTag(tag, object string) error {
+	return runGitCommandVoid("update-ref", "refs/tags/"+tag, object)
+}
+
+// TagExists reports whether a tag with the given name exists locally.
+// parameters:
+// - tag: the name of the tag to look up
+// returns:
+// - bool: true if the tag exists, otherwise false
+func TagExists(tag string) bool {
+	return runGitCommandVoid("rev-parse", "-q", "--verify", "refs/tags/"+tag) == nil
+}
+
+// FindUntagged retrieves the details of the commits of a branch that none of the given tagged commits
+// contains in a single query. Callers pass the commits of version tags only, so that other tags, e.g.
+// deploy-prod or vendor-sync, do not mark commits as tagged.
+// parameters:
+// - branch: the branch from which to find untagged commits
+// - tagged: the commits of the version tags
+// - firstParent: only follow the first parent of merge commits, skipping commits of merged branches
+// returns:
+// - []Commit: the untagged commits, oldest first
+// - error: an error object if something went wrong, otherwise nil
+func FindUntagged(branch string, tagged []string, firstParent bool) ([]Commit, error) {
+	args := []string{"log", "--reverse", "--format=" + commitFormat + recordSep}
+	if firstParent {
+		args = append(args, "--first-parent")
 	}
 
-	// sort tags to find the latest version
-	sort.Slice(semVerTags, func(i, j int) bool {
-		return utils.CompareSemVer(semVerTags[i], semVerTags[j]) < 0
-	})
+	// exclude the commits reachable from the tagged commits, following all parents of tagged commits
+	args = append(args, branch)
+	if len(tagged) > 0 {
+		args = append(append(args, "--not"), tagged...)
+	}
+	out, err := run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untagged commits: %w", err)
+	}
+	return parseCommits(out)
+}
 
-	// return the highest (latest) version tag
-	return semVerTags[len(semVerTags)-1], nil
+// GetTagsWithDates retrieves all tags together with their creation dates.
+// returns:
+// - []TagInfo: the tags of the repository
+// - error: an error object if something went wrong, otherwise nil
+func GetTagsWithDates() ([]TagInfo, error) {
+	return ListTags("")
 }
 
-// GetTagsForCommit retrieves tags for a specific commit.
+// ListTags retrieves tags with their creation dates and commits in a single query.
 // parameters:
-// - commit: the commit hash for which to retrieve associated tags
+// - contains: a commit the tags must contain, or empty for all tags
 // returns:
-// - []string: a slice of tag names associated with the commit
+// - []TagInfo: the tags
 // - error: an error object if something went wrong, otherwise nil
-func GetTagsForCommit(commit string) ([]string, error) {
-	tags, err := RunGitCommand("tag", "--contains", commit)
+func ListTags(contains string) ([]TagInfo, error) {
+	// annotated tags point to the commit through %(*objectname), lightweight tags through %(objectname)
+	args := []string{"for-each-ref", "--format=%(refname:short)%09%(creatordate:unix)%09%(objectname)%09%(*objectname)"}
+	if contains != "" {
+		args = append(args, "--contains", contains)
+	}
+	lines, err := RunGitCommand(append(args, "refs/tags")...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve tags for commit %s: %w", commit, err)
+		return nil, fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+
+	tags := make([]TagInfo, 0, len(lines))
+	for _, line := range lines {
+		// output lines are trimmed, dropping the empty %(*objectname) of lightweight tags
+		fields := strings.Split(line, "\t")
+		if len(fields) == 3 {
+			fields = append(fields, "")
+		}
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date of tag %s: %w", fields[0], err)
+		}
+		tag := TagInfo{Name: fields[0], Date: time.Unix(seconds, 0), Commit: fields[2]}
+		if fields[3] != "" {
+			tag.Commit = fields[3]
+		}
+		tags = append(tags, tag)
 	}
 	return tags, nil
 }
 
+// GetTagGraph retrieves the parents of every commit reachable from the tags starting with a prefix, in a
+// single query.
+// parameters:
+// - prefix: the prefix of the tags, e.g. v
+// returns:
+// - map[string][]string: the parent hashes of each commit, first parent first
+// - error: an error object if something went wrong, otherwise nil
+func GetTagGraph(prefix string) (map[string][]string, error) {
+	lines, err := RunGitCommand("rev-list", "--parents", "--glob=refs/tags/"+prefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the history of tags %s*: %w", prefix, err)
+	}
+	graph := make(map[string][]string, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		graph[fields[0]] = fields[1:]
+	}
+	return graph, nil
+}
+
 // // ---------- Commit Functions ----------
 
 // GetShortCommitHash retrieves the short form of a given commit hash.
@@ -106,27 +322,213 @@ func GetTagsForCommit(commit string) ([]string, error) {
 // - string: the short form of the commit hash
 // - error: an error object if something went wrong, otherwise nil
 func GetShortCommitHash(commit string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--short", commit)
-	out, err := cmd.Output()
+	out, err := run("rev-parse", "--short", commit)
 	if err != nil {
-		return "", utils.WrapErrorf("failed to get short hash: %w", err)
+		return "", fmt.Errorf("failed to get short hash: %w", err)
 	}
-	return strings.TrimSpace(string(out)), nil
+	return strings.TrimSpace(out), nil
 }
 
-// GetCommitMessage retrieves the commit message for a given commit hash.
+// ShowCommit retrieves the full message, file statistics and diff of a commit, without colors.
 // parameters:
-// - commit: the commit hash for which to retrieve the message
+// - commit: the commit hash or revision to show
 // returns:
-// - string: the commit message
+// - string: the output of git show
 // - error: an error object if something went wrong, otherwise nil
-func GetCommitMessage(commit string) (string, error) {
-	cmd := exec.Command("git", "show", "-s", "--format=%s", commit)
-	out, err := cmd.Output()
+func ShowCommit(commit string) (string, error) {
+	out, err := run("show", "--no-color", "--format=fuller", "--stat", "--patch", commit)
 	if err != nil {
-		return "", utils.WrapErrorf("failed to get commit message: %w", err)
+		return "", fmt.Errorf("failed to show commit %s: %w", commit, err)
+	}
+	return out, nil
+}
+
+// GetCommit retrieves the hash, subject, body and trailers of a commit.
+// parameters:
+// - commit: the commit hash or revision to look up
+// returns:
+// - Commit: the commit details
+// - error: an error object if something went wrong, otherwise nil
+func GetCommit(commit string) (Commit, error) {
+	out, err := run("show", "-s", "--format="+commitFormat, commit)
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to get commit %s: %w", commit, err)
+	}
+	return parseCommit(out)
+}
+
+// GetCommits retrieves the details of the commits in a revision range (e.g. v1.0.0..HEAD) in a single
+// query, oldest first. A single revision yields just that commit.
+// parameters:
+// - revRange: the revision or revision range to list
+// returns:
+// - []Commit: the commit details
+// - error: an error object if something went wrong, otherwise nil
+func GetCommits(revRange string) ([]Commit, error) {
+	args := []string{"log", "--reverse", "--format=" + commitFormat + recordSep, revRange}
+	if !strings.Contains(revRange, "..") {
+		args = []string{"log", "--no-walk", "--format=" + commitFormat + recordSep, revRange}
+	}
+	out, err := run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in %s: %w", revRange, err)
+	}
+
+	return parseCommits(out)
+}
+
+// parseCommits parses the output of git log with commitFormat followed by recordSep.
+func parseCommits(out string) ([]Commit, error) {
+	var commits []Commit
+	for _, record := range strings.Split(out, recordSep) {
+		if record = strings.TrimLeft(record, "\n"); record == "" {
+			continue
+		}
+		commit, err := parseCommit(record)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
 	}
-	return strings.TrimSpace(string(out)), nil
+	return commits, nil
+}
+
+// GetCommitsBetween retrieves the details of the commits reachable from to but not from from in a single
+// query, oldest first and parents before their children.
+// parameters:
+// - from: the exclusive start of the range, or empty for the whole history of to
+// - to: the inclusive end of the range
+// returns:
+// - []Commit: the commit details
+// - error: an error object if something went wrong, otherwise nil
+func GetCommitsBetween(from, to string) ([]Commit, error) {
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	out, err := run("log", "--reverse", "--topo-order", "--format="+commitFormat+recordSep, revRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", revRange, err)
+	}
+	return parseCommits(out)
+}
+
+// GetParents retrieves the parent hashes of a commit; merge commits have more than one.
+// parameters:
+// - commit: the commit hash to look up
+// returns:
+// - []string: the parent commit hashes, first parent first
+// - error: an error object if something went wrong, otherwise nil
+func GetParents(commit string) ([]string, error) {
+	out, err := RunGitCommand("rev-list", "--parents", "-n", "1", commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parents of %s: %w", commit, err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("failed to get parents of %s: empty output", commit)
+	}
+	return strings.Fields(out[0])[1:], nil
+}
+
+// parseCommit parses the output of git show with commitFormat.
+func parseCommit(out string) (Commit, error) {
+	fields := strings.SplitN(out, "\x00", 9)
+	if len(fields) != 9 {
+		return Commit{}, fmt.Errorf("unexpected commit format: %q", out)
+	}
+
+	seconds, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to parse commit date %q: %w", fields[4], err)
+	}
+
+	c := Commit{
+		Hash:        strings.TrimSpace(fields[0]),
+		ShortHash:   fields[1],
+		Author:      fields[2],
+		AuthorEmail: fields[3],
+		Date:        time.Unix(seconds, 0),
+		Subject:     strings.TrimSpace(fields[5]),
+		Body:        strings.TrimSpace(fields[6]),
+		Parents:     strings.Fields(fields[8]),
+	}
+	for _, line := range strings.Split(fields[7], "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			c.Trailers = append(c.Trailers, line)
+		}
+	}
+	return c, nil
+}
+
+// CreateCommit stages the given paths and commits only them, leaving other staged changes out of the commit.
+// Hooks triggered by the commit see ToolCommitEnv, so that the post-commit hook does not tag it again.
+// parameters:
+// - message: the commit message
+// - opts: the paths to commit and the identities to commit with
+// returns:
+// - string: the full hash of the new commit
+// - error: an error object if something went wrong, otherwise nil
+func CreateCommit(message string, opts CommitOptions) (string, error) {
+	if len(opts.Paths) == 0 {
+		return "", fmt.Errorf("failed to create commit: no paths given")
+	}
+	if err := runGitCommandVoid(append([]string{"add", "--"}, opts.Paths...)...); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", strings.Join(opts.Paths, ", "), err)
+	}
+
+	env := []string{ToolCommitEnv + "=1"}
+	for name, value := range map[string]string{
+		"GIT_AUTHOR_NAME":     opts.Author.Name,
+		"GIT_AUTHOR_EMAIL":    opts.Author.Email,
+		"GIT_COMMITTER_NAME":  opts.Committer.Name,
+		"GIT_COMMITTER_EMAIL": opts.Committer.Email,
+	} {
+		if value != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	if _, err := runWithEnv(env, append([]string{"commit", "-q", "-m", message, "--"}, opts.Paths...)...); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", strings.Join(opts.Paths, ", "), err)
+	}
+	return ResolveCommit("HEAD")
+}
+
+// ResetKeep moves the current branch to a commit with git reset --keep, updating the files that differ
+// and refusing if one of them has local changes.
+// parameters:
+// - commit: the commit to move the branch to
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func ResetKeep(commit string) error {
+	return runGitCommandVoid("reset", "-q", "--keep", commit)
+}
+
+// IsToolCommit reports whether the process was started by a hook of a commit created by CreateCommit.
+// returns:
+// - bool: true if the current commit was created by git-tagger itself
+func IsToolCommit() bool {
+	return os.Getenv(ToolCommitEnv) != ""
+}
+
+// ChangedPaths returns the paths that have uncommitted changes, including untracked files.
+// parameters:
+// - paths: the paths (or pathspecs) to check
+// returns:
+// - []string: the paths with changes, in the given order
+// - error: an error object if something went wrong, otherwise nil
+func ChangedPaths(paths ...string) ([]string, error) {
+	var changed []string
+	for _, path := range paths {
+		out, err := RunGitCommand("status", "--porcelain", "--untracked-files=all", "--", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s for changes: %w", path, err)
+		}
+		if len(out) > 0 {
+			changed = append(changed, path)
+		}
+	}
+	return changed, nil
 }
 
 // ---------- Branch Functions ----------
@@ -149,58 +551,165 @@ func GetBranches() ([]string, error) {
 	return branches, nil
 }
 
+// IsAncestor reports whether a commit is contained in the history of a revision, e.g. of a branch.
+// Parameters:
+// - commit: the revision to look for
+// - rev: the revision whose history is searched
+// Returns:
+// - bool: true if rev contains commit, including when both are the same commit
+// - error: An error object if something went wrong, otherwise nil
+func IsAncestor(commit, rev string) (bool, error) {
+	err := runGitCommandVoid("merge-base", "--is-ancestor", commit, rev)
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) && cmdErr.ExitCode == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether %s contains %s: %w", rev, commit, err)
+	}
+	return true, nil
+}
+
 // GetCurrentBranch retrieves the name of the currently checked-out branch.
 // Returns:
 // - string: The name of the current branch
-// - error: An error object if something went wrong, otherwise nil
+// - error: ErrDetachedHead if HEAD is detached, otherwise an error object if something went wrong
 func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	out, err := cmd.Output()
+	out, err := run("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
-		return "", utils.WrapErrorf("failed to get current branch: %w", err)
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
 	// Ensure any extra spaces or newlines are trimmed
-	branchName := strings.TrimSpace(string(out))
+	branchName := strings.TrimSpace(out)
 	if branchName == "HEAD" {
-		return "", fmt.Errorf("detached HEAD state: not currently on any branch")
+		return "", ErrDetachedHead
 	}
 
 	return branchName, nil
 }
 
-/* SelectBranch lets the user choose a branch from the list of branches
+// ---------- Repository Functions ----------
+
+// operationFiles maps files in the Git directory of a worktree to the operation they indicate is in progress.
+var operationFiles = []struct {
+	Path      string
+	Operation string
+}{
+	{"MERGE_HEAD", "merge"},
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
+
+// UncommittedChanges lists the tracked files with staged or unstaged changes, ignoring untracked files.
+// returns:
+// - []string: the changed files in git status --porcelain format (e.g. " M README")
+// - error: an error object if something went wrong, otherwise nil
+func UncommittedChanges() ([]string, error) {
+	out, err := RunGitCommand("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	return out, nil
+}
+
+// CommitsBehindUpstream counts the commits of the upstream branch of a branch that the branch lacks,
+// using the local remote-tracking refs (i.e. as of the last fetch).
 // parameters:
-// - branches: a slice of strings containing the branch names to choose from
+// - branch: the local branch to check
 // returns:
-// - string: the name of the selected branch
+// - string: the upstream branch, or empty if the branch has none
+// - int: the number of commits the branch is behind its upstream
 // - error: an error object if something went wrong, otherwise nil
-func SelectBranch(branches []string) (string, error) {
-	fmt.Println("Select a branch to update:")
-	for i, branch := range branches {
-		// Print the branch options, trimming any extra whitespace
-		fmt.Printf("[%d] %s\n", i+1, strings.TrimSpace(branch))
+func CommitsBehindUpstream(branch string) (string, int, error) {
+	out, err := RunGitCommand("for-each-ref", "--format=%(upstream:short)", "refs/heads/"+branch)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get upstream of %s: %w", branch, err)
 	}
+	if len(out) == 0 {
+		return "", 0, nil
+	}
+	upstream := out[0]
 
-	// Prompt for user input
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Enter the number of the branch: ")
-	choiceStr, err := reader.ReadString('\n')
+	counts, err := RunGitCommand("rev-list", "--count", "refs/heads/"+branch+".."+upstream)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return "", 0, fmt.Errorf("failed to compare %s with %s: %w", branch, upstream, err)
 	}
+	behind, err := strconv.Atoi(counts[0])
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse commit count %q: %w", counts[0], err)
+	}
+	return upstream, behind, nil
+}
 
-	// Convert the choice to an integer
-	choiceStr = strings.TrimSpace(choiceStr)
-	choice, err := strconv.Atoi(choiceStr)
-	if err != nil || choice < 1 || choice > len(branches) {
-		return "", fmt.Errorf("invalid selection: %s", choiceStr)
+// InProgressOperation reports a merge, rebase, cherry-pick, revert or bisect in progress in the current worktree.
+// returns:
+// - string: the name of the operation, or empty if none is in progress
+// - error: an error object if something went wrong, otherwise nil
+func InProgressOperation() (string, error) {
+	args := []string{"rev-parse", "--path-format=absolute"}
+	for _, f := range operationFiles {
+		args = append(args, "--git-path", f.Path)
+	}
+	paths, err := RunGitCommand(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git state files: %w", err)
 	}
 
-	// Return the selected branch
-	return strings.TrimSpace(branches[choice-1]), nil
+	for i, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return operationFiles[i].Operation, nil
+		}
+	}
+	return "", nil
+}
+
+// GetGitDir retrieves the common Git directory of the repository, shared by all worktrees.
+// returns:
+// - string: the absolute path to the Git directory
+// - error: an error object if something went wrong, otherwise nil
+func GetGitDir() (string, error) {
+	out, err := RunGitCommand("rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git directory: %w", err)
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("failed to get git directory: empty output")
+	}
+	return out[0], nil
+}
+
+// GetRepoRoot retrieves the top-level directory of the current working tree.
+// returns:
+// - string: the path to the repository root
+// - error: an error object if something went wrong, otherwise nil
+func GetRepoRoot() (string, error) {
+	out, err := RunGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("failed to get repository root: empty output")
+	}
+	return out[0], nil
+}
+
+// ResolveCommit resolves a revision (branch, tag, HEAD, short hash) to a full commit hash.
+// parameters:
+// - rev: the revision to resolve
+// returns:
+// - string: the full commit hash
+// - error: an error object if something went wrong, otherwise nil
+func ResolveCommit(rev string) (string, error) {
+	out, err := RunGitCommand("rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return out[0], nil
 }
- */
 
 // ---------- Utility Functions ----------
 
@@ -209,28 +718,34 @@ func SelectBranch(branches []string) (string, error) {
 // - args: the arguments for the git command
 // returns:
 // - []string: the output lines from the git command
-// - error: an error object if something went wrong, otherwise nil
+// - error: a *CommandError if the command failed, otherwise nil
 func RunGitCommand(args ...string) ([]string, error) {
-	cmd := exec.Command("git", args...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to run git command: %w", err)
+	out, err := run(args...)
+	if err != nil {
+		return nil, err
 	}
+	return utils.FilterEmptyStrings(strings.Split(strings.TrimSpace(out), "\n")), nil
+}
 
-	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
-	return utils.FilterEmptyStrings(lines), nil
+// batchStrings splits a slice into consecutive batches of at most size elements.
+func batchStrings(items []string, size int) [][]string {
+	var batches [][]string
+	for len(items) > size {
+		batches = append(batches, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		batches = append(batches, items)
+	}
+	return batches
 }
 
 // runGitCommandVoid executes a git command without requiring the output
 // parameters:
 // - args: the arguments for the git command
 // returns:
-// - error: an error object if something went wrong, otherwise nil
+// - error: a *CommandError if the command failed, otherwise nil
 func runGitCommandVoid(args ...string) error {
-	cmd := exec.Command("git", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run git command: %w", err)
-	}
-	return nil
+	_, err := run(args...)
+	return err
 }
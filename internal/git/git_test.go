@@ -1,7 +1,11 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"git-tagger/internal/testutils"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +37,15 @@ func TestCreateTag(t *testing.T) {
 	}
 
 	testutils.VerifyTagExists(t, tagName)
+
+	err := CreateTag(tagName, message, "HEAD")
+	if !errors.Is(err, ErrTagExists) {
+		t.Fatalf("Expected ErrTagExists when creating %s again, got %v", tagName, err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || !strings.Contains(cmdErr.Stderr, "already exists") || cmdErr.ExitCode == 0 {
+		t.Errorf("Expected the error to hold the error output of git, got %#v", cmdErr)
+	}
 }
 
 // TestGetCurrentBranch verifies that the current Git branch is correctly identified as "master".
@@ -44,6 +57,14 @@ func TestCreateTag(t *testing.T) {
 func TestGetCurrentBranch(t *testing.T) {
 	testutils.SetupTestRepo(t)
 	testutils.ValidateCurrentBranch(t, "master")
+
+	testutils.CreateAndCommitFile(t, "file1.txt", "Initial commit")
+	if err := runGitCommandVoid("checkout", "-q", "--detach"); err != nil {
+		t.Fatalf("Failed to detach HEAD: %v", err)
+	}
+	if _, err := GetCurrentBranch(); !errors.Is(err, ErrDetachedHead) {
+		t.Errorf("Expected ErrDetachedHead, got %v", err)
+	}
 }
 
 // TestGetBranches validates the functionality of listing branches in a Git repository.
@@ -59,3 +80,127 @@ func TestGetBranches(t *testing.T) {
 
 	testutils.ValidateBranches(t, []string{"feature/test-branch", "master"})
 }
+
+// TestCreateCommit validates that only the given paths are committed, with the given author.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestCreateCommit(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	if err := os.WriteFile("VERSION", []byte("1.4.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write VERSION: %v", err)
+	}
+	if err := os.WriteFile("notes.txt", []byte("unrelated\n"), 0644); err != nil {
+		t.Fatalf("Failed to write notes.txt: %v", err)
+	}
+
+	hash, err := CreateCommit("chore(release): v1.4.0", CommitOptions{
+		Paths:  []string{"VERSION"},
+		Author: Identity{Name: "Release Bot", Email: "bot@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCommit failed: %v", err)
+	}
+
+	out, err := RunGitCommand("show", "--name-only", "--format=%an%n%s", hash)
+	if err != nil {
+		t.Fatalf("Failed to show commit: %v", err)
+	}
+	if expected := []string{"Release Bot", "chore(release): v1.4.0", "VERSION"}; strings.Join(out, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected commit %v, got %v", expected, out)
+	}
+
+	changed, err := ChangedPaths("VERSION", "notes.txt")
+	if err != nil {
+		t.Fatalf("ChangedPaths failed: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "notes.txt" {
+		t.Errorf("Expected only notes.txt to be left uncommitted, got %v", changed)
+	}
+}
+
+// TestRunner validates that the runner reports the command and error output of failed commands,
+// and that cancelled commands report the cancellation.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRunner(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	testutils.CreateAndCommitFile(t, "file1.txt", "Initial commit")
+	r := &Runner{}
+
+	result, err := r.Run(context.Background(), "rev-parse", "HEAD")
+	if err != nil || len(strings.TrimSpace(result.Stdout)) != 40 || result.Stderr != "" {
+		t.Fatalf("Expected the hash of HEAD on stdout only, got %+v, %v", result, err)
+	}
+
+	_, err = r.Run(context.Background(), "rev-parse", "--verify", "no-such-branch")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode == 0 || cmdErr.Stderr == "" {
+		t.Fatalf("Expected a CommandError with the error output of git, got %#v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "git rev-parse --verify no-such-branch: ") {
+		t.Errorf("Expected the error to name the command, got %q", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Run(ctx, "status"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestBatchQueries validates that commits and tags are read with their parents and target commits in
+// single queries, for lightweight as well as annotated tags.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestBatchQueries(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	testutils.CreateAndCommitFile(t, "file1.txt", "feat: first\n\nwith a body")
+	if err := CreateTag("v1.0.0", "Version 1.0.0", "HEAD"); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	testutils.CreateAndCommitFile(t, "file2.txt", "fix: second")
+	if err := testutils.RunGitCommand("tag", "v1.0.1"); err != nil {
+		t.Fatalf("Failed to create a lightweight tag: %v", err)
+	}
+
+	commits, err := GetCommitsBetween("", "HEAD")
+	if err != nil || len(commits) != 3 {
+		t.Fatalf("Expected the 3 commits of the history, got %d (%v)", len(commits), err)
+	}
+	if commits[1].Subject != "feat: first" || commits[1].Body != "with a body" {
+		t.Errorf("Expected the second commit to be the feature with its body, got %+v", commits[1])
+	}
+	if len(commits[0].Parents) != 0 || len(commits[2].Parents) != 1 || commits[2].Parents[0] != commits[1].Hash {
+		t.Errorf("Expected the parents of the commits to be read, got %v and %v", commits[0].Parents, commits[2].Parents)
+	}
+	if between, err := GetCommitsBetween("v1.0.0", "HEAD"); err != nil || len(between) != 1 || between[0].Hash != commits[2].Hash {
+		t.Errorf("Expected only the fix after v1.0.0, got %+v (%v)", between, err)
+	}
+	if short := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "--short", "HEAD"); commits[2].ShortHash != short {
+		t.Errorf("Expected the short hash %s of the fix, got %q", short, commits[2].ShortHash)
+	}
+	if untagged, err := FindUntagged("HEAD", []string{commits[1].Hash}, false); err != nil || len(untagged) != 1 || untagged[0].Subject != "fix: second" {
+		t.Errorf("Expected only the fix to be untagged, got %+v (%v)", untagged, err)
+	}
+
+	tags, err := ListTags(commits[2].Hash)
+	if err != nil || len(tags) != 1 || tags[0].Name != "v1.0.1" || tags[0].Commit != commits[2].Hash {
+		t.Errorf("Expected the lightweight tag v1.0.1 to contain the fix, got %+v (%v)", tags, err)
+	}
+	tags, err = ListTags("")
+	if err != nil || len(tags) != 2 || tags[0].Name != "v1.0.0" || tags[0].Commit != commits[1].Hash {
+		t.Errorf("Expected the annotated tag v1.0.0 to point to the feature, got %+v (%v)", tags, err)
+	}
+}
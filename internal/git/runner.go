@@ -0,0 +1,183 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"git-tagger/internal/logging"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Backend runs git commands for the functions of this package. Runner is the implementation
+// executing the git binary; other implementations can e.g. record or fake commands in tests.
+// Implementations must pass the variables of EnvFromContext to git.
+type Backend interface {
+	Run(ctx context.Context, args ...string) (Result, error)
+}
+
+// Runner runs git commands, capturing their output and error output separately.
+type Runner struct {
+	Dir     string        // working directory of git, empty for the current directory
+	Env     []string      // additional environment variables, e.g. GIT_AUTHOR_NAME=...
+	Timeout time.Duration // maximum duration of a single command, 0 for no limit
+}
+
+// Result is the output of a successful git command.
+type Result struct {
+	Stdout string // untrimmed standard output
+	Stderr string // trimmed error output, e.g. warnings
+}
+
+// contextBackend runs the commands of a backend with a fixed context.
+type contextBackend struct {
+	backend Backend
+	ctx     context.Context
+}
+
+// envKey is the context key of the environment variables added by WithEnv.
+type envKey struct{}
+
+// backend runs the commands of the package functions, replaced by SetBackend.
+var backend Backend = &Runner{}
+
+// ---------- Backend Functions ----------
+
+// SetBackend replaces the backend used by the functions of this package, e.g. with a runner with a timeout.
+// parameters:
+// - b: the backend to use from now on
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// GetBackend returns the backend used by the functions of this package.
+// returns:
+// - Backend: the current backend
+func GetBackend() Backend {
+	return backend
+}
+
+// WithContext returns a backend running all commands of another backend with the given context,
+// so that the functions of this package, which take no context, can be cancelled.
+// parameters:
+// - ctx: the context of all commands
+// - b: the backend to run the commands
+// returns:
+// - Backend: the bound backend
+func WithContext(ctx context.Context, b Backend) Backend {
+	return contextBackend{backend: b, ctx: ctx}
+}
+
+// WithEnv returns a context whose git commands run with additional environment variables.
+// parameters:
+// - ctx: the parent context
+// - env: the environment variables in NAME=value form
+// returns:
+// - context.Context: the derived context
+func WithEnv(ctx context.Context, env ...string) context.Context {
+	return context.WithValue(ctx, envKey{}, append(EnvFromContext(ctx), env...))
+}
+
+// EnvFromContext returns the environment variables added to a context by WithEnv.
+// parameters:
+// - ctx: the context of a command
+// returns:
+// - []string: the environment variables in NAME=value form
+func EnvFromContext(ctx context.Context) []string {
+	env, _ := ctx.Value(envKey{}).([]string)
+	return append([]string{}, env...)
+}
+
+// Run runs a command of the bound backend with the bound context, keeping the environment of ctx.
+func (b contextBackend) Run(ctx context.Context, args ...string) (Result, error) {
+	return b.backend.Run(WithEnv(b.ctx, EnvFromContext(ctx)...), args...)
+}
+
+// Run runs a git command. The command is killed when the context is cancelled or the timeout of the
+// runner expires. Every command is logged at debug level, its output at trace level.
+// parameters:
+// - ctx: the context of the command
+// - args: the arguments for the git command, without "git"
+// returns:
+// - Result: the output of the command
+// - error: a *CommandError holding the arguments and error output of git if the command failed, otherwise nil
+func (r *Runner) Run(ctx context.Context, args ...string) (Result, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	if env := append(append([]string{}, r.Env...), EnvFromContext(ctx)...); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{Stdout: stdout.String(), Stderr: strings.TrimSpace(stderr.String())}
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		exitCode = -1
+	}
+	logging.Logger().Debug("git "+strings.Join(args, " "), "duration", time.Since(start).Round(time.Microsecond), "exit_code", exitCode)
+
+	if err != nil {
+		// report cancellation and timeouts rather than the signal that killed git
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return result, &CommandError{Args: args, ExitCode: exitCode, Stderr: result.Stderr, Err: err}
+	}
+	logging.Logger().Log(ctx, logging.LevelTrace, "git output", "lines", strings.Split(strings.TrimSpace(result.Stdout), "\n"))
+	return result, nil
+}
+
+// ---------- Helper Functions ----------
+
+// run runs a git command with the backend.
+// parameters:
+// - args: the arguments for the git command
+// returns:
+// - string: the untrimmed standard output of the command
+// - error: a *CommandError if the command failed, otherwise nil
+func run(args ...string) (string, error) {
+	result, err := backend.Run(context.Background(), args...)
+	return result.Stdout, err
+}
+
+// runWithEnv runs a git command with the backend and additional environment variables.
+// parameters:
+// - env: the additional environment variables
+// - args: the arguments for the git command
+// returns:
+// - string: the untrimmed standard output of the command
+// - error: a *CommandError if the command failed, otherwise nil
+func runWithEnv(env []string, args ...string) (string, error) {
+	result, err := backend.Run(WithEnv(context.Background(), env...), args...)
+	return result.Stdout, err
+}
+
+// quoteArgs formats command arguments for error messages, quoting arguments with spaces.
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -1,7 +1,7 @@
 package hooks
 
 import (
-	"fmt"
+	"git-tagger/internal/logging"
 	"git-tagger/internal/utils"
 	"os"
 	"path/filepath"
@@ -82,7 +82,7 @@ func CleanGitHook() error {
 		}
 	}
 
-	fmt.Println("Successfully cleaned post-commit hook.")
+	logging.Logger().Debug("Cleaned post-commit hook", "path", hookDest)
 	return nil
 }
 
@@ -108,7 +108,7 @@ func InstallGitHook(executablePath string) error {
 	// Check if the hook file already exists
 	if _, err := os.Stat(hookDest); err == nil {
 		// Hook exists, check for the necessary content
-		fmt.Println("A post-commit hook already exists. Checking for required content...")
+		logging.Logger().Debug("A post-commit hook already exists, checking for required content", "path", hookDest)
 
 		// Read the current hook content using hookContainsFullContent
 		hookContains, err := utils.HookContainsFullContent(hookDest, hookContent)
@@ -117,7 +117,7 @@ func InstallGitHook(executablePath string) error {
 		}
 
 		if hookContains {
-			fmt.Println("The post-commit hook already contains the necessary content.")
+			logging.Logger().Info("The post-commit hook already contains the necessary content.")
 			return nil
 		}
 
@@ -126,12 +126,12 @@ func InstallGitHook(executablePath string) error {
 		if err != nil {
 			return utils.WrapErrorf("failed to append content to existing post-commit hook: %w", err)
 		}
-		fmt.Println("Appended content to existing post-commit hook.")
+		logging.Logger().Info("Appended content to existing post-commit hook.")
 		return nil
 	}
 
 	// If the hook does not exist, create it and add the necessary content
-	fmt.Println("No existing post-commit hook found. Installing new hook.")
+	logging.Logger().Debug("No existing post-commit hook found, installing new hook", "path", hookDest)
 
 	// Write the new hook content
 	err = utils.WriteFile(hookDest, hookContent)
@@ -139,6 +139,6 @@ func InstallGitHook(executablePath string) error {
 		return utils.WrapErrorf("failed to write post-commit hook: %w", err)
 	}
 
-	fmt.Println("Post-commit hook installed successfully.")
+	logging.Logger().Debug("Installed post-commit hook", "path", hookDest)
 	return nil
 }
@@ -0,0 +1,108 @@
+package issues
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultPatterns match GitHub and GitLab issue numbers (#123).
+var defaultPatterns = []string{
+	`(?:^|[^\w&/])(#\d+)\b`,
+}
+
+// projectPattern matches Jira keys (PROJ-123) of the projects substituted for %s. It is only used with
+// configured projects, as keys of standards such as UTF-8 or ISO-8601 look the same.
+const projectPattern = `\b((?:%s)-[1-9]\d*)\b`
+
+// Extractor finds issue references in commit messages.
+type Extractor struct {
+	patterns []*regexp.Regexp
+	projects []string // accepted Jira-style project keys; empty accepts all
+}
+
+// match is an issue reference found at a position of a text.
+type match struct {
+	pos   int
+	issue string
+}
+
+// ---------- Issue Functions ----------
+
+// NewExtractor creates an extractor from the issue configuration. Custom patterns are matched
+// in addition to the built-in ones unless the built-in patterns are disabled. Jira keys are only
+// matched for the configured projects.
+// parameters:
+// - cfg: the issue configuration
+// returns:
+// - *Extractor: the extractor
+// - error: an error object if a pattern is invalid, otherwise nil
+func NewExtractor(cfg config.IssuesConfig) (*Extractor, error) {
+	patterns := cfg.Patterns
+	if !cfg.DisableDefaults {
+		defaults := slices.Clone(defaultPatterns)
+		if len(cfg.Projects) > 0 {
+			keys := make([]string, len(cfg.Projects))
+			for i, project := range cfg.Projects {
+				keys[i] = regexp.QuoteMeta(project)
+			}
+			defaults = append(defaults, fmt.Sprintf(projectPattern, strings.Join(keys, "|")))
+		}
+		patterns = append(defaults, patterns...)
+	}
+
+	e := &Extractor{projects: cfg.Projects}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issue pattern %q: %w", pattern, err)
+		}
+		e.patterns = append(e.patterns, re)
+	}
+	return e, nil
+}
+
+// Extract returns the issues referenced in the subject, body and trailers of a commit, in order of
+// first appearance. A pattern's first group is the issue reference, or the whole match if it has none.
+// parameters:
+// - commit: the commit to scan
+// returns:
+// - []string: the referenced issues
+func (e *Extractor) Extract(commit git.Commit) []string {
+	var issues []string
+	for _, text := range append([]string{commit.Subject, commit.Body}, commit.Trailers...) {
+		var found []match
+		for _, re := range e.patterns {
+			for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+				start, end := m[0], m[1]
+				if len(m) > 2 && m[2] >= 0 {
+					start, end = m[2], m[3]
+				}
+				found = append(found, match{start, text[start:end]})
+			}
+		}
+		slices.SortStableFunc(found, func(a, b match) int { return a.pos - b.pos })
+
+		for _, f := range found {
+			if e.accepts(f.issue) && !slices.Contains(issues, f.issue) {
+				issues = append(issues, f.issue)
+			}
+		}
+	}
+	return issues
+}
+
+// ---------- Helper Functions ----------
+
+// accepts reports whether an issue belongs to one of the configured projects, if any are configured.
+// Issues without a project key (e.g. #123) are always accepted.
+func (e *Extractor) accepts(issue string) bool {
+	key, _, found := strings.Cut(issue, "-")
+	if !found || strings.HasPrefix(issue, "#") || len(e.projects) == 0 {
+		return true
+	}
+	return slices.Contains(e.projects, key)
+}
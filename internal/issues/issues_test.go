@@ -0,0 +1,50 @@
+package issues
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"reflect"
+	"testing"
+)
+
+// TestExtract validates extraction of GitHub, Jira and custom issue references from all parts of a commit message,
+// and that Jira keys are only matched for configured projects.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestExtract(t *testing.T) {
+	commit := git.Commit{
+		Subject:  "fix(api): handle empty body (#123)",
+		Body:     "Also see PROJ-7, EN-12 and ticket 4711; not refs: UTF-8, SHA-256, ISO-8601 or &#39;",
+		Trailers: []string{"Closes #123", "Refs: PROJ-456"},
+	}
+
+	tests := []struct {
+		cfg      config.IssuesConfig
+		expected []string
+	}{
+		{config.IssuesConfig{}, []string{"#123"}},
+		{config.IssuesConfig{Projects: []string{"EN", "PROJ"}}, []string{"#123", "PROJ-7", "EN-12", "PROJ-456"}},
+		{config.IssuesConfig{Projects: []string{"PROJ", "SHA"}}, []string{"#123", "PROJ-7", "SHA-256", "PROJ-456"}},
+		{config.IssuesConfig{Projects: []string{"PROJ"}}, []string{"#123", "PROJ-7", "PROJ-456"}},
+		{config.IssuesConfig{Patterns: []string{`ticket (\d+)`}, DisableDefaults: true}, []string{"4711"}},
+		{config.IssuesConfig{Patterns: []string{`[A-Z]+-\d+`}, DisableDefaults: true, Projects: []string{"ISO"}}, []string{"ISO-8601"}},
+	}
+
+	for _, tt := range tests {
+		extractor, err := NewExtractor(tt.cfg)
+		if err != nil {
+			t.Fatalf("NewExtractor failed: %v", err)
+		}
+		if got := extractor.Extract(commit); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Extract with %+v: expected %v, got %v", tt.cfg, tt.expected, got)
+		}
+	}
+
+	if _, err := NewExtractor(config.IssuesConfig{Patterns: []string{"("}}); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,348 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"git-tagger/internal/git"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	journalDir = "git-tagger/runs" // location of run journals, relative to the Git directory
+
+	StatusInProgress = "in-progress" // the run is still creating tags (or crashed mid-run)
+	StatusCompleted  = "completed"   // the run finished and all tags were kept
+	StatusRolledBack = "rolled-back" // the run failed and its tags were removed automatically
+	StatusUndone     = "undone"      // the run's tags were removed with the undo command
+)
+
+// Entry is a single tag created during a run.
+type Entry struct {
+	Tag       string    `json:"tag"`
+	Commit    string    `json:"commit"`
+	CreatedAt time.Time `json:"created_at"`
+	Replaced  string    `json:"replaced,omitempty"` // object the tag pointed to before a forced replacement, restored by Rollback
+	Pending   bool      `json:"pending,omitempty"`  // set until Confirm, i.e. the run may have died before creating the tag
+}
+
+// CommitEntry is a release commit created during a run.
+type CommitEntry struct {
+	Commit    string    `json:"commit"`
+	Parent    string    `json:"parent"` // commit the branch pointed to before, restored by Rollback
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Journal records every tag and release commit created by a single tagging run so the run can be reverted.
+type Journal struct {
+	RunID     string        `json:"run_id"`
+	Branch    string        `json:"branch"`
+	Status    string        `json:"status"`
+	StartedAt time.Time     `json:"started_at"`
+	Tags      []Entry       `json:"tags"`
+	Commits   []CommitEntry `json:"commits,omitempty"`
+
+	path string
+}
+
+// ---------- Journal Functions ----------
+
+// Start creates and persists a new journal for a tagging run on the given branch.
+// parameters:
+// - branch: the branch being tagged
+// returns:
+// - *Journal: the newly created journal
+// - error: an error object if something went wrong, otherwise nil
+func Start(branch string) (*Journal, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	// Reserve the journal file, numbering runs started in the same second by the same process
+	now := time.Now().UTC()
+	base := now.Format("20060102T150405Z") + "-" + strconv.Itoa(os.Getpid())
+	runID := base
+	for n := 2; ; n++ {
+		file, err := os.OpenFile(filepath.Join(dir, runID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			file.Close()
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to create journal: %w", err)
+		}
+		runID = base + "-" + strconv.Itoa(n)
+	}
+
+	j := &Journal{
+		RunID:     runID,
+		Branch:    branch,
+		Status:    StatusInProgress,
+		StartedAt: now,
+		path:      filepath.Join(dir, runID+".json"),
+	}
+	if err := j.save(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Load reads the journal of the run with the given ID.
+// parameters:
+// - runID: the identifier of the run
+// returns:
+// - *Journal: the loaded journal
+// - error: an error object if the ID is not a run ID, e.g. holds a path, or the journal cannot be read
+func Load(runID string) (*Journal, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) || strings.Contains(runID, "..") {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+	return load(filepath.Join(dir, runID+".json"))
+}
+
+// List returns all recorded runs, oldest first.
+// returns:
+// - []*Journal: the journals of all recorded runs
+// - error: an error object if something went wrong, otherwise nil
+func List() ([]*Journal, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journals: %w", err)
+	}
+
+	var journals []*Journal
+	for _, file := range files {
+		j, err := load(file)
+		if err != nil {
+			return nil, err
+		}
+		journals = append(journals, j)
+	}
+
+	sort.Slice(journals, func(i, k int) bool {
+		return journals[i].StartedAt.Before(journals[k].StartedAt)
+	})
+	return journals, nil
+}
+
+// Latest returns the most recent run that still has tags to undo.
+// returns:
+// - *Journal: the most recent undoable journal
+// - error: an error object if something went wrong or no such run exists
+func Latest() (*Journal, error) {
+	journals, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(journals) - 1; i >= 0; i-- {
+		j := journals[i]
+		if j.Status != StatusUndone && j.Status != StatusRolledBack && len(j.Tags) > 0 {
+			return j, nil
+		}
+	}
+	return nil, errors.New("no tagging runs to undo")
+}
+
+// Record appends a tag about to be created to the journal and persists it immediately, so the tag can be
+// reverted even if the process dies while or after creating it. Confirm marks the tag as created.
+// parameters:
+// - tag: the name of the tag
+// - commit: the commit the tag will point at
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (j *Journal) Record(tag, commit string) error {
+	return j.RecordReplacement(tag, commit, "")
+}
+
+// RecordReplacement appends a tag about to replace an existing tag of the same name to the journal and
+// persists it immediately, so that rolling back restores the replaced tag instead of deleting it.
+// parameters:
+// - tag: the name of the tag
+// - commit: the commit the tag will point at
+// - replaced: the object the tag points to before, as returned by git.GetTagObject, or empty if the tag is new
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (j *Journal) RecordReplacement(tag, commit, replaced string) error {
+	j.Tags = append(j.Tags, Entry{Tag: tag, Commit: commit, CreatedAt: time.Now().UTC(), Replaced: replaced, Pending: true})
+	return j.save()
+}
+
+// Confirm marks the latest recorded entry of a tag as created and persists the journal.
+// parameters:
+// - tag: the name of the created tag
+// returns:
+// - error: an error object if the tag was not recorded or the journal cannot be written, otherwise nil
+func (j *Journal) Confirm(tag string) error {
+	for i := len(j.Tags) - 1; i >= 0; i-- {
+		if j.Tags[i].Tag == tag {
+			j.Tags[i].Pending = false
+			return j.save()
+		}
+	}
+	return fmt.Errorf("tag %s is not recorded in run %s", tag, j.RunID)
+}
+
+// RecordCommit appends a release commit created on top of the checked-out commit to the journal and
+// persists it immediately, so that rolling back moves the branch back to the parent.
+// parameters:
+// - commit: the full hash of the release commit
+// - parent: the full hash of the commit the branch pointed to before
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (j *Journal) RecordCommit(commit, parent string) error {
+	j.Commits = append(j.Commits, CommitEntry{Commit: commit, Parent: parent, CreatedAt: time.Now().UTC()})
+	return j.save()
+}
+
+// Finish marks the run with a final status.
+// parameters:
+// - status: the final status of the run
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (j *Journal) Finish(status string) error {
+	j.Status = status
+	return j.save()
+}
+
+// Rollback deletes every tag recorded in the journal, newest first, restores the tags they replaced and
+// resets the release commits of the run, which must still be checked out. Tags that no longer exist
+// locally or on the remote and commits that are already reset are skipped. A tag that was never confirmed
+// is only deleted if it points at its recorded commit, as the run may have died before creating it.
+// parameters:
+// - remote: the remote to delete the tags from as well, or empty to only delete local tags
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func (j *Journal) Rollback(remote string) error {
+	var errs []error
+	var deleted []string
+	for i := len(j.Tags) - 1; i >= 0; i-- {
+		tag := j.Tags[i].Tag
+
+		if replaced := j.Tags[i].Replaced; replaced != "" {
+			if err := git.RestoreTag(tag, replaced); err != nil {
+				errs = append(errs, fmt.Errorf("failed to restore tag %s: %w", tag, err))
+				continue
+			}
+			if remote != "" {
+				if err := git.PushTags(remote, true, tag); err != nil {
+					errs = append(errs, fmt.Errorf("failed to restore tag %s on %s: %w", tag, remote, err))
+				}
+			}
+			continue
+		}
+
+		if j.Tags[i].Pending && !created(j.Tags[i]) {
+			continue
+		}
+		if git.TagExists(tag) {
+			if err := git.DeleteTags(tag); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete tag %s: %w", tag, err))
+				continue
+			}
+		}
+		deleted = append(deleted, tag)
+	}
+
+	// tags that were never pushed are skipped, so partly pushed runs can be undone
+	if remote != "" && len(deleted) > 0 {
+		if err := git.DeleteRemoteTags(remote, deleted...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete tags from %s: %w", remote, err))
+		}
+	}
+
+	for i := len(j.Commits) - 1; i >= 0; i-- {
+		if err := resetCommit(j.Commits[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ---------- Helper Functions ----------
+
+// created reports whether the tag of an unconfirmed entry exists and points at the recorded commit.
+func created(entry Entry) bool {
+	if !git.TagExists(entry.Tag) {
+		return false
+	}
+	tagged, err := git.ResolveCommit("refs/tags/" + entry.Tag)
+	if err != nil {
+		return false
+	}
+	commit, err := git.ResolveCommit(entry.Commit)
+	return err == nil && tagged == commit
+}
+
+// resetCommit moves the checked-out branch from a release commit back to its parent, keeping local changes.
+func resetCommit(entry CommitEntry) error {
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	switch head {
+	case entry.Parent:
+		return nil
+	case entry.Commit:
+		if err := git.ResetKeep(entry.Parent); err != nil {
+			return fmt.Errorf("failed to reset release commit %.7s: %w", entry.Commit, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("release commit %.7s is no longer checked out; revert it manually", entry.Commit)
+}
+
+// runsDir returns the directory where run journals are stored.
+func runsDir() (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, journalDir), nil
+}
+
+// load reads a journal from the given file.
+func load(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", path, err)
+	}
+	j.path = path
+	return &j, nil
+}
+
+// save writes the journal to disk.
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := os.WriteFile(j.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
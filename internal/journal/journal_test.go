@@ -0,0 +1,217 @@
+package journal
+
+import (
+	"git-tagger/internal/git"
+	"git-tagger/internal/testutils"
+	"testing"
+)
+
+// TestRecordAndRollback verifies that tags recorded in a run journal are deleted by Rollback
+// and that the run can be reloaded from disk.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRecordAndRollback(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	testutils.CreateAndCommitFile(t, "file1.txt", "feat: first feature")
+
+	run, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	for _, tag := range []string{"v0.1.0-aaaaaaa", "v0.1.1-bbbbbbb"} {
+		if err := git.CreateTag(tag, "test", "HEAD"); err != nil {
+			t.Fatalf("CreateTag failed: %v", err)
+		}
+		if err := run.Record(tag, "HEAD"); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := run.Finish(StatusCompleted); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	latest, err := Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest.RunID != run.RunID || len(latest.Tags) != 2 {
+		t.Fatalf("Unexpected latest run: %+v", latest)
+	}
+
+	if err := latest.Rollback(""); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	for _, entry := range latest.Tags {
+		if git.TagExists(entry.Tag) {
+			t.Errorf("Tag %s still exists after rollback", entry.Tag)
+		}
+	}
+
+	// Rolling back again must be a no-op for tags that are already gone
+	if err := latest.Rollback(""); err != nil {
+		t.Fatalf("Second rollback failed: %v", err)
+	}
+}
+
+// TestRollbackRestoresReplaced verifies that rolling back a forced replacement restores the replaced tag
+// instead of deleting it.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRollbackRestoresReplaced(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	if err := git.CreateTag("v1.0.0", "original", "HEAD"); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	original, err := git.GetTagObject("v1.0.0")
+	if err != nil {
+		t.Fatalf("GetTagObject failed: %v", err)
+	}
+	testutils.CreateAndCommitFile(t, "file1.txt", "fix: second")
+
+	run, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := git.CreateTagWithOptions("v1.0.0", "replacement", "HEAD", git.TagOptions{Force: true}); err != nil {
+		t.Fatalf("CreateTagWithOptions failed: %v", err)
+	}
+	if err := run.RecordReplacement("v1.0.0", "HEAD", original); err != nil {
+		t.Fatalf("RecordReplacement failed: %v", err)
+	}
+
+	if err := run.Rollback(""); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if restored, err := git.GetTagObject("v1.0.0"); err != nil || restored != original {
+		t.Errorf("Expected v1.0.0 to point to %s again, got %s (%v)", original, restored, err)
+	}
+}
+
+// TestRollbackPartlyPushed verifies that rolling back a run whose tags were only partly pushed deletes
+// the pushed tags from the remote and skips the others.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRollbackPartlyPushed(t *testing.T) {
+	remote := t.TempDir()
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"init", "-q", "--bare", remote},
+		{"remote", "add", "origin", remote},
+		{"tag", "v1.0.0-aaaaaaa"},
+		{"tag", "v1.0.1-bbbbbbb"},
+		{"push", "-q", "origin", "refs/tags/v1.0.0-aaaaaaa"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	run, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for _, tag := range []string{"v1.0.0-aaaaaaa", "v1.0.1-bbbbbbb"} {
+		if err := run.Record(tag, "HEAD"); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	if err := run.Rollback("origin"); err != nil {
+		t.Fatalf("Rollback of a partly pushed run failed: %v", err)
+	}
+	if tags, err := git.ListRemoteTags("origin"); err != nil || len(tags) != 0 {
+		t.Errorf("Expected no tags left on the remote, got %v (%v)", tags, err)
+	}
+	if err := run.Rollback("origin"); err != nil {
+		t.Errorf("Expected a second rollback to succeed, got %v", err)
+	}
+}
+
+// TestRollbackPending verifies that rolling back keeps tags the run recorded but may have died before
+// creating, unless they point at the recorded commit.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRollbackPending(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	if err := git.CreateTag("v1.0.0", "foreign", "HEAD"); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	testutils.CreateAndCommitFile(t, "file1.txt", "feat: first feature")
+
+	run, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for _, tag := range []string{"v1.0.0", "v1.1.0", "v1.2.0"} {
+		if err := run.Record(tag, "HEAD"); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	// the run died after creating v1.1.0, before confirming it
+	if err := git.CreateTag("v1.1.0", "test", "HEAD"); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+
+	loaded, err := Load(run.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := loaded.Rollback(""); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if git.TagExists("v1.1.0") {
+		t.Errorf("Expected the unconfirmed v1.1.0 at the recorded commit to be deleted")
+	}
+	if !git.TagExists("v1.0.0") {
+		t.Errorf("Expected v1.0.0, which points at another commit, to be kept")
+	}
+}
+
+// TestRunIDs verifies that runs started at once get distinct IDs and that Load rejects IDs holding a path.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRunIDs(t *testing.T) {
+	testutils.SetupTestRepo(t)
+
+	first, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	second, err := Start("master")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if first.RunID == second.RunID {
+		t.Errorf("Expected distinct run IDs, got %s twice", first.RunID)
+	}
+
+	for _, runID := range []string{"", "..", "../runs/" + first.RunID, "runs/" + first.RunID, `..\runs\` + first.RunID} {
+		if _, err := Load(runID); err == nil {
+			t.Errorf("Expected Load to reject run ID %q", runID)
+		}
+	}
+	if _, err := Load(second.RunID); err != nil {
+		t.Errorf("Expected Load to read run %s, got %v", second.RunID, err)
+	}
+}
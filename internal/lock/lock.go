@@ -0,0 +1,166 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/logging"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockFile = "git-tagger/lock" // location of the lock file, relative to the common Git directory
+
+	pollInterval = 200 * time.Millisecond // interval of checks while waiting for the lock
+)
+
+// ErrLocked is returned when another tagging run holds the lock of the repository.
+var ErrLocked = errors.New("repository is locked by another tagging run")
+
+// Lock is an advisory lock of a repository, held by a single tagging run across all worktrees.
+type Lock struct {
+	PID      int       `json:"pid"`
+	Host     string    `json:"host"`
+	Acquired time.Time `json:"acquired"`
+
+	path string
+}
+
+// ---------- Lock Functions ----------
+
+// Acquire takes the lock of the current repository. A lock left behind by a process that no longer runs
+// on this host is removed. If another run holds the lock, Acquire waits for it if cfg.Wait is set, up to
+// cfg.Timeout, and otherwise fails immediately.
+// parameters:
+// - ctx: the context, whose cancellation stops waiting
+// - cfg: the lock configuration
+// returns:
+// - *Lock: the acquired lock, to be released with Release
+// - error: an error wrapping ErrLocked if the lock is held by another run, otherwise an error object if something went wrong
+func Acquire(ctx context.Context, cfg config.LockConfig) (*Lock, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(gitDir, lockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	host, _ := os.Hostname()
+	l := &Lock{PID: os.Getpid(), Host: host, path: path}
+
+	deadline := time.Now().Add(cfg.Timeout)
+	waiting := false
+	for {
+		holder, err := l.tryCreate()
+		if err != nil {
+			return nil, err
+		}
+		if holder == nil {
+			return l, nil
+		}
+
+		if holder.PID == 0 {
+			// released in the meantime
+			continue
+		}
+		if holder.stale() {
+			// another waiting run may have replaced the stale lock with its own in the meantime
+			if current, err := readHolder(path); err == nil && current.PID == holder.PID && current.Acquired.Equal(holder.Acquired) {
+				logging.Logger().Warn("Removing stale lock of a process that is no longer running", "pid", holder.PID, "path", path)
+				if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("failed to remove stale lock: %w", err)
+				}
+			}
+			continue
+		}
+
+		if !cfg.Wait || time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w (process %d on %s since %s); remove %s if that process is gone",
+				ErrLocked, holder.PID, holder.Host, holder.Acquired.Format(time.RFC3339), path)
+		}
+		if !waiting {
+			logging.Logger().Info("Waiting for another tagging run to finish...", "pid", holder.PID)
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release removes the lock. Releasing a nil lock does nothing, so that it can be deferred right after Acquire.
+// returns:
+// - error: an error object if the lock file could not be removed, otherwise nil
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// ---------- Helper Functions ----------
+
+// tryCreate creates the lock file if it does not exist.
+// returns:
+// - *Lock: the current holder of the lock if the file exists, or nil if the lock was acquired
+// - error: an error object if something went wrong, otherwise nil
+func (l *Lock) tryCreate() (*Lock, error) {
+	l.Acquired = time.Now().UTC()
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lock: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return readHolder(l.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lock: %w", err)
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(l.path)
+		return nil, fmt.Errorf("failed to write lock: %w", err)
+	}
+	return nil, nil
+}
+
+// readHolder reads the lock file of the run holding the lock. A file that is being written or cannot be
+// parsed counts as held by an unknown, live process (PID -1); a file removed in the meantime yields PID 0.
+func readHolder(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Lock{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock: %w", err)
+	}
+
+	holder := &Lock{PID: -1}
+	_ = json.Unmarshal(data, holder)
+	return holder, nil
+}
+
+// stale reports whether the lock was left behind by a process of this host that no longer runs.
+func (l *Lock) stale() bool {
+	host, _ := os.Hostname()
+	return l.PID > 0 && l.Host == host && !processAlive(l.PID)
+}
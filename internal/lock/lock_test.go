@@ -0,0 +1,64 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"git-tagger/internal/config"
+	"git-tagger/internal/testutils"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// TestAcquire validates that a held lock is refused or waited for, and that locks of exited processes are taken over.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestAcquire(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	ctx := context.Background()
+
+	held, err := Acquire(ctx, config.LockConfig{})
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if _, err := Acquire(ctx, config.LockConfig{}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected ErrLocked without waiting, got %v", err)
+	}
+
+	// a waiting run gets the lock once it is released
+	go func() {
+		time.Sleep(2 * pollInterval)
+		_ = held.Release()
+	}()
+	waited, err := Acquire(ctx, config.LockConfig{Wait: true, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Expected to get the lock after waiting, got %v", err)
+	}
+	if err := waited.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	// a lock of a process that exited is stale
+	exited := exec.Command("git", "--version")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("Failed to run process: %v", err)
+	}
+	host, _ := os.Hostname()
+	data, _ := json.Marshal(Lock{PID: exited.Process.Pid, Host: host, Acquired: time.Now()})
+	if err := os.WriteFile(".git/"+lockFile, data, 0644); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+	stale, err := Acquire(ctx, config.LockConfig{})
+	if err != nil {
+		t.Fatalf("Expected the stale lock to be replaced, got %v", err)
+	}
+	if err := stale.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package lock
+
+// processAlive reports whether a process with the given PID runs. Without a portable check, locks are
+// never considered stale; they expire with the lock timeout of the waiting run instead.
+func processAlive(pid int) bool {
+	return true
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	LevelTrace = slog.LevelDebug - 4 // git command output and other very detailed messages
+
+	FormatText = "text" // human-readable messages on stderr
+	FormatJSON = "json" // one JSON object per message, e.g. for CI log processing
+)
+
+// logger is the logger shared by all packages, replaced by SetLogger.
+var logger = New(Options{})
+
+// Options controls the logger created by New.
+type Options struct {
+	Verbosity int       // -1 shows only warnings and errors, 0 informational messages, 1 debug messages and git commands, 2 everything
+	Format    string    // FormatText (default) or FormatJSON
+	Writer    io.Writer // destination of log messages (default: stderr)
+}
+
+// ---------- Logging Functions ----------
+
+// New creates a logger for the given verbosity and format.
+// parameters:
+// - opts: the verbosity, format and destination of the logger
+// returns:
+// - *slog.Logger: the logger
+func New(opts Options) *slog.Logger {
+	w := opts.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case opts.Verbosity < 0:
+		level = slog.LevelWarn
+	case opts.Verbosity == 1:
+		level = slog.LevelDebug
+	case opts.Verbosity > 1:
+		level = LevelTrace
+	}
+
+	if opts.Format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: replaceLevel}))
+	}
+	return slog.New(&textHandler{w: w, level: level, mu: &sync.Mutex{}})
+}
+
+// Logger returns the shared logger.
+// returns:
+// - *slog.Logger: the logger used by all packages
+func Logger() *slog.Logger {
+	return logger
+}
+
+// SetLogger replaces the shared logger, e.g. with one created from command-line flags.
+// parameters:
+// - l: the logger to use from now on
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// ---------- Helper Functions ----------
+
+// replaceLevel names the trace level in JSON output instead of reporting it as DEBUG-4.
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok && level <= LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+	}
+	return a
+}
+
+// textHandler writes messages as plain lines for terminals: informational messages as they are,
+// other levels with a prefix, and attributes as key=value pairs. An "error" attribute is appended
+// to the message after a colon.
+type textHandler struct {
+	w      io.Writer
+	level  slog.Level
+	attrs  []slog.Attr
+	prefix string // group prefix of attribute keys
+	mu     *sync.Mutex
+}
+
+// Enabled reports whether messages of the given level are written.
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes a message.
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var line strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		line.WriteString("error: ")
+	case r.Level >= slog.LevelWarn:
+		line.WriteString("warning: ")
+	case r.Level >= slog.LevelInfo:
+	case r.Level >= slog.LevelDebug:
+		line.WriteString("debug: ")
+	default:
+		line.WriteString("trace: ")
+	}
+	line.WriteString(r.Message)
+
+	var errText string
+	write := func(a slog.Attr) {
+		switch {
+		case a.Key == "error":
+			errText = a.Value.String()
+		case !a.Equal(slog.Attr{}):
+			fmt.Fprintf(&line, " %s=%s", a.Key, quote(a.Value.Resolve().String()))
+		}
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		a.Key = h.prefix + a.Key
+		write(a)
+		return true
+	})
+	if errText != "" {
+		line.WriteString(": " + errText)
+	}
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+// WithAttrs returns a handler that adds the given attributes to every message.
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that prefixes the keys of later attributes with the group name.
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// quote quotes values containing spaces or quotes so that key=value pairs stay unambiguous.
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
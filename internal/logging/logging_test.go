@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNew validates the levels enabled by each verbosity and the text and JSON output formats.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		verbosity int
+		expected  string
+	}{
+		{-1, "warning: careful\n"},
+		{0, "working\nwarning: careful\n"},
+		{1, "debug: git status exit_code=0\nworking\nwarning: careful\n"},
+		{2, "trace: git output lines=2\ndebug: git status exit_code=0\nworking\nwarning: careful\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		logger := New(Options{Verbosity: tt.verbosity, Writer: &out})
+		logger.Log(context.Background(), LevelTrace, "git output", "lines", 2)
+		logger.Debug("git status", "exit_code", 0)
+		logger.Info("working")
+		logger.Warn("careful")
+		if out.String() != tt.expected {
+			t.Errorf("Verbosity %d: expected %q, got %q", tt.verbosity, tt.expected, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	New(Options{Writer: &out}).Error("Failed to tag", "error", errors.New("tag exists"))
+	if out.String() != "error: Failed to tag: tag exists\n" {
+		t.Errorf("Unexpected error output: %q", out.String())
+	}
+
+	out.Reset()
+	New(Options{Format: FormatJSON, Verbosity: 2, Writer: &out}).Log(context.Background(), LevelTrace, "git output")
+	if !strings.Contains(out.String(), `"level":"TRACE"`) || !strings.Contains(out.String(), `"msg":"git output"`) {
+		t.Errorf("Unexpected JSON output: %q", out.String())
+	}
+}
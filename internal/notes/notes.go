@@ -0,0 +1,242 @@
+package notes
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/issues"
+	"git-tagger/internal/version"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var (
+	conventionalPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+	breakingPattern     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: ?`)
+	coAuthorPattern     = regexp.MustCompile(`(?i)^Co-authored-by: *(.+?) *<[^>]*>$`)
+)
+
+// groups lists the commit groups of release notes in order, by Conventional Commits type.
+// Commits of other types and non-conventional commits are listed under "Other Changes".
+var groups = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+	{"refactor", "Code Refactoring"},
+	{"docs", "Documentation"},
+	{"", "Other Changes"},
+}
+
+// Markdown is the built-in release notes template.
+const Markdown = `## {{.Tag}} ({{.Date.Format "2006-01-02"}})
+{{- if .Breaking}}
+
+### ⚠ BREAKING CHANGES
+{{range .Breaking}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{- end}}
+{{- end}}
+{{- range .Groups}}
+
+### {{.Title}}
+{{range .Commits}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+{{- if .Issues}}
+
+### Issues
+
+{{join .Issues ", "}}
+{{- end}}
+{{- if .Contributors}}
+
+### Contributors
+
+{{join .Contributors ", "}}
+{{- end}}
+`
+
+// Notes holds the changes between a version tag and its predecessor, as passed to templates.
+type Notes struct {
+	Tag          string
+	Previous     string    // preceding tag, empty for the first version
+	Date         time.Time // date of the tagged commit
+	Groups       []Group   // non-empty commit groups in display order
+	Breaking     []Change  // breaking changes
+	Commits      []Change  // all commits of the range, oldest first
+	Contributors []string  // authors and co-authors, in order of first appearance
+	Issues       []string  // referenced issues, in order of first appearance
+}
+
+// Group is a list of commits of the same type.
+type Group struct {
+	Type    string
+	Title   string
+	Commits []Change
+}
+
+// Change is a commit, or for breaking changes the description of what broke.
+type Change struct {
+	Hash        string
+	ShortHash   string
+	Type        string // Conventional Commits type, empty for non-conventional commits
+	Scope       string
+	Description string // subject without type and scope (for grouped types), or the text of a BREAKING CHANGE footer
+	Author      string
+	Issues      []string
+}
+
+// ---------- Notes Functions ----------
+
+// Build collects the release notes of a version tag from the commits since its predecessor.
+// parameters:
+// - tag: the version tag to describe
+// - from: the tag to start from, or empty to use the preceding version tag
+// - cfg: the repository configuration
+// returns:
+// - *Notes: the collected release notes
+// - error: an error object if something went wrong, otherwise nil
+func Build(tag, from string, cfg *config.Config) (*Notes, error) {
+	if !git.TagExists(tag) {
+		return nil, fmt.Errorf("tag %s does not exist", tag)
+	}
+
+	if from == "" {
+		previous, err := version.PreviousTag(tag, cfg)
+		if err != nil {
+			return nil, err
+		}
+		from = previous
+	}
+
+	commits, err := git.GetCommitsBetween(from, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := issues.NewExtractor(cfg.Issues)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notes{Tag: tag, Previous: from}
+	for _, commit := range commits {
+		n.add(commit, extractor.Extract(commit))
+	}
+
+	tagged, err := git.GetCommit(tag)
+	if err != nil {
+		return nil, err
+	}
+	n.Date = tagged.Date
+
+	for _, g := range groups {
+		group := Group{Type: g.Type, Title: g.Title}
+		for _, c := range n.Commits {
+			if groupType(c.Type) == g.Type {
+				group.Commits = append(group.Commits, c)
+			}
+		}
+		if len(group.Commits) > 0 {
+			n.Groups = append(n.Groups, group)
+		}
+	}
+	return n, nil
+}
+
+// Render renders release notes through a text/template, or through the built-in Markdown template if
+// the template is empty. Templates can use the fields of Notes and the join function.
+// parameters:
+// - n: the release notes to render
+// - text: the template to render with
+// returns:
+// - string: the rendered notes
+// - error: an error object if the template is invalid or fails, otherwise nil
+func Render(n *Notes, text string) (string, error) {
+	if text == "" {
+		text = Markdown
+	}
+
+	tmpl, err := template.New("notes").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid notes template: %w", err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, n); err != nil {
+		return "", fmt.Errorf("failed to render notes: %w", err)
+	}
+	return out.String(), nil
+}
+
+// ---------- Helper Functions ----------
+
+// add adds a commit and its referenced issues to the notes, collecting its breaking changes and contributors.
+func (n *Notes) add(commit git.Commit, referenced []string) {
+	change := Change{
+		Hash:        commit.Hash,
+		ShortHash:   commit.Hash[:min(7, len(commit.Hash))],
+		Description: commit.Subject,
+		Author:      commit.Author,
+		Issues:      referenced,
+	}
+
+	bang := false
+	if m := conventionalPattern.FindStringSubmatch(commit.Subject); m != nil {
+		change.Type, change.Scope, bang = strings.ToLower(m[1]), m[2], m[3] == "!"
+		if groupType(change.Type) != "" {
+			// other types keep their full subject, as "Other Changes" does not name their type
+			change.Description = m[4]
+		} else {
+			change.Scope = ""
+		}
+	}
+	n.Commits = append(n.Commits, change)
+
+	// BREAKING CHANGE footers describe the break; a bare "!" falls back to the subject
+	if loc := breakingPattern.FindStringIndex(commit.Body); loc != nil {
+		description, _, _ := strings.Cut(commit.Body[loc[1]:], "\n\n")
+		breaking := change
+		breaking.Description = strings.Join(strings.Fields(description), " ")
+		n.Breaking = append(n.Breaking, breaking)
+	} else if bang {
+		n.Breaking = append(n.Breaking, change)
+	}
+
+	contributors := []string{commit.Author}
+	for _, trailer := range commit.Trailers {
+		if m := coAuthorPattern.FindStringSubmatch(trailer); m != nil {
+			contributors = append(contributors, m[1])
+		}
+	}
+	n.Contributors = appendUnique(n.Contributors, contributors...)
+	n.Issues = appendUnique(n.Issues, change.Issues...)
+}
+
+// groupType returns the type of the group listing commits of the given type.
+func groupType(commitType string) string {
+	for _, g := range groups {
+		if g.Type == commitType {
+			return commitType
+		}
+	}
+	return ""
+}
+
+// appendUnique appends the values that are not yet in the slice, keeping their order.
+func appendUnique(values []string, add ...string) []string {
+	for _, v := range add {
+		if v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
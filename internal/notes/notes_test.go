@@ -0,0 +1,67 @@
+package notes
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/testutils"
+	"strings"
+	"testing"
+)
+
+// TestBuildAndRender validates that release notes cover the commits since the preceding release,
+// grouped by type, with breaking changes, issues and contributors.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestBuildAndRender(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"tag", "v1.0.0"},
+		{"commit", "--allow-empty", "-m", "feat(api): add users endpoint (#12)"},
+		{"tag", "v1.1.0-rc.1"},
+		{"commit", "--allow-empty", "-m", "fix: crash on empty input", "-m", "Refs: PROJ-7"},
+		{"commit", "--allow-empty", "-m", "feat!: drop v1 routes", "-m", "BREAKING CHANGE: the /v1 routes\nare gone.", "-m", "Co-authored-by: Carol <carol@example.com>"},
+		{"tag", "v2.0.0"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Issues.Projects = []string{"PROJ"}
+	n, err := Build("v2.0.0", "", cfg)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if n.Previous != "v1.0.0" || len(n.Commits) != 3 {
+		t.Fatalf("Expected 3 commits since v1.0.0, got %d since %q", len(n.Commits), n.Previous)
+	}
+
+	out, err := Render(n, "")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, expected := range []string{
+		"## v2.0.0 (",
+		"### ⚠ BREAKING CHANGES\n\n- the /v1 routes are gone.\n",
+		"### Features\n\n- **api:** add users endpoint (#12) (",
+		"### Bug Fixes\n\n- crash on empty input (",
+		"#12, PROJ-7",
+		"testuser, Carol",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected notes to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	out, err = Render(n, "{{.Previous}}..{{.Tag}}{{range .Groups}} {{.Type}}{{end}}")
+	if err != nil {
+		t.Fatalf("Render with custom template failed: %v", err)
+	}
+	if out != "v1.0.0..v2.0.0 feat fix" {
+		t.Errorf("Unexpected custom rendering: %q", out)
+	}
+}
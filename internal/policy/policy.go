@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"path"
+)
+
+const (
+	Release    = "release"    // the branch may create release, pre-release and build tags
+	Prerelease = "prerelease" // the branch may only create pre-release and build tags
+	Forbidden  = "forbidden"  // the branch may not be tagged
+
+	KindRelease    = "release"     // a stable release tag, e.g. v1.4.0
+	KindPrerelease = "pre-release" // a pre-release tag, e.g. v1.4.0-rc.1
+	KindBuild      = "build"       // a tag with a hash suffix created for untagged commits, e.g. v1.4.0-1a2b3c4
+)
+
+// ErrNotAllowed is returned when the policy of a branch does not allow a kind of tag.
+var ErrNotAllowed = errors.New("not allowed by branch policy")
+
+// ---------- Policy Functions ----------
+
+// For returns the policy of a branch.
+// parameters:
+// - branch: the branch name, or empty for a detached HEAD
+// - cfg: the branch policies
+// returns:
+// - string: Release, Prerelease or Forbidden
+func For(branch string, cfg config.BranchesConfig) string {
+	switch {
+	case branch == "":
+	case MatchBranch(cfg.Forbidden, branch):
+		return Forbidden
+	case MatchBranch(cfg.Release, branch):
+		return Release
+	case MatchBranch(cfg.Prerelease, branch):
+		return Prerelease
+	}
+	if cfg.Default == "" {
+		return Release
+	}
+	return cfg.Default
+}
+
+// Allow checks that the policy of a branch allows creating a kind of tag.
+// parameters:
+// - branch: the branch to tag, or empty for the current branch
+// - cfg: the branch policies
+// - kind: KindRelease, KindPrerelease or KindBuild
+// returns:
+// - error: an error wrapping ErrNotAllowed if the policy does not allow the tag, otherwise an error object if something went wrong, or nil
+func Allow(branch string, cfg config.BranchesConfig, kind string) error {
+	if branch == "" {
+		current, err := git.GetCurrentBranch()
+		if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+			return err
+		}
+		branch = current
+	}
+	return check(branch, cfg, kind)
+}
+
+// AllowCommit checks that a kind of tag may be created for a commit of the branch being tagged. A commit
+// the branch does not contain is judged like a detached HEAD, so that other branches containing it cannot
+// authorize the tag.
+// parameters:
+// - commit: the revision to tag
+// - branch: the branch being tagged, or empty for the current branch
+// - cfg: the branch policies
+// - kind: KindRelease, KindPrerelease or KindBuild
+// returns:
+// - string: the branch whose policy was applied, or empty for a detached HEAD
+// - error: an error wrapping ErrNotAllowed if the policy does not allow the tag, otherwise an error object if something went wrong, or nil
+func AllowCommit(commit, branch string, cfg config.BranchesConfig, kind string) (string, error) {
+	if branch == "" {
+		current, err := git.GetCurrentBranch()
+		if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+			return "", err
+		}
+		branch = current
+	}
+	if branch != "" {
+		contains, err := git.IsAncestor(commit, "refs/heads/"+branch)
+		if err != nil {
+			return "", err
+		}
+		if !contains {
+			branch = ""
+		}
+	}
+	return branch, check(branch, cfg, kind)
+}
+
+// MatchBranch reports whether a branch matches one of the given glob patterns (e.g. main, release/*).
+// parameters:
+// - patterns: the patterns, as understood by path.Match
+// - branch: the branch name
+// returns:
+// - bool: true if a pattern matches the branch
+func MatchBranch(patterns []string, branch string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------- Helper Functions ----------
+
+// check checks that the policy of a branch allows creating a kind of tag.
+// parameters:
+// - branch: the branch name, or empty for a detached HEAD
+// - cfg: the branch policies
+// - kind: KindRelease, KindPrerelease or KindBuild
+// returns:
+// - error: an error wrapping ErrNotAllowed if the policy does not allow the tag, otherwise an error object if the policy is invalid, or nil
+func check(branch string, cfg config.BranchesConfig, kind string) error {
+	name := branch
+	if name == "" {
+		name = "detached HEAD"
+	}
+	switch p := For(branch, cfg); {
+	case p == Forbidden:
+		return fmt.Errorf("%w: %s may not be tagged", ErrNotAllowed, name)
+	case p == Prerelease && kind == KindRelease:
+		return fmt.Errorf("%w: %s may only create pre-release and build tags", ErrNotAllowed, name)
+	case p != Release && p != Prerelease:
+		return fmt.Errorf("invalid branch policy %q: expected release, prerelease or forbidden", p)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"errors"
+	"git-tagger/internal/config"
+	"git-tagger/internal/testutils"
+	"testing"
+)
+
+// TestAllow validates that branches get the policy of the first matching list and that each policy allows its kinds of tags.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestAllow(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	cfg := config.BranchesConfig{
+		Release:    []string{"main", "release/*"},
+		Prerelease: []string{"develop", "release/*"},
+		Forbidden:  []string{"wip/*"},
+		Default:    Prerelease,
+	}
+
+	tests := []struct {
+		branch  string
+		kind    string
+		allowed bool
+	}{
+		{"main", KindRelease, true},
+		{"release/1.x", KindRelease, true},
+		{"develop", KindRelease, false},
+		{"develop", KindPrerelease, true},
+		{"develop", KindBuild, true},
+		{"feature/login", KindRelease, false},
+		{"feature/login", KindBuild, true},
+		{"wip/spike", KindBuild, false},
+		{"", KindPrerelease, true}, // the current branch of the test repository gets the default policy
+	}
+	for _, tt := range tests {
+		err := Allow(tt.branch, cfg, tt.kind)
+		if tt.allowed && err != nil {
+			t.Errorf("Expected %s tags on %q to be allowed, got %v", tt.kind, tt.branch, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("Expected %s tags on %q to be refused, got %v", tt.kind, tt.branch, err)
+		}
+	}
+
+	if got := For("main", config.BranchesConfig{}); got != Release {
+		t.Errorf("Expected branches to be release branches by default, got %q", got)
+	}
+	if err := Allow("main", config.BranchesConfig{Default: "sometimes"}, KindBuild); err == nil || errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected an invalid default policy to be reported, got %v", err)
+	}
+}
+
+// TestAllowCommit validates that a commit is judged by the branch being tagged, that other branches containing
+// it cannot authorize the tag, and that a commit the branch does not contain is judged like a detached HEAD.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestAllowCommit(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	initial := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD")
+	for _, args := range [][]string{
+		{"checkout", "-q", "-b", "wip/spike"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: spike"},
+		{"branch", "main", initial},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	cfg := config.BranchesConfig{Release: []string{"main"}, Forbidden: []string{"wip/*"}, Default: Prerelease}
+
+	if _, err := AllowCommit("HEAD", "", cfg, KindBuild); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected a commit of the current branch wip/spike to be refused, got %v", err)
+	}
+	if _, err := AllowCommit(initial, "", cfg, KindRelease); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected main, which also contains the commit, not to authorize a release from wip/spike, got %v", err)
+	}
+	if branch, err := AllowCommit(initial, "main", cfg, KindRelease); err != nil || branch != "main" {
+		t.Errorf("Expected main to allow releasing its own commit, got %q (%v)", branch, err)
+	}
+	if _, err := AllowCommit("HEAD", "main", cfg, KindRelease); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected a commit main does not contain to get the default policy, got %v", err)
+	}
+	if branch, err := AllowCommit("HEAD", "main", cfg, KindPrerelease); err != nil || branch != "" {
+		t.Errorf("Expected a commit main does not contain to be judged as a detached HEAD, got %q (%v)", branch, err)
+	}
+
+	if err := testutils.RunGitCommand("checkout", "-q", "--detach", "main"); err != nil {
+		t.Fatalf("git checkout failed: %v", err)
+	}
+	if _, err := AllowCommit("HEAD", "", cfg, KindRelease); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected a detached HEAD to get the default policy, got %v", err)
+	}
+}
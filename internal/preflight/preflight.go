@@ -0,0 +1,111 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"strings"
+)
+
+// Errors of failed checks, checkable with errors.Is. Uncommitted changes and a detached HEAD are
+// reported as git.ErrDirtyTree and git.ErrDetachedHead.
+var (
+	ErrFailed              = errors.New("preflight checks failed")
+	ErrBehindUpstream      = errors.New("branch is behind its upstream")
+	ErrOperationInProgress = errors.New("an operation is in progress")
+)
+
+// Failure is the error of failed checks, matching ErrFailed and the errors of the individual checks.
+type Failure struct {
+	Reasons []error // errors of the failed checks
+}
+
+// ---------- Preflight Functions ----------
+
+// Check runs the configured checks before tagging the untagged commits of a branch: HEAD is on a branch,
+// the tree has no uncommitted changes, the branch is not behind its upstream, and no merge
+// or rebase is in progress.
+// parameters:
+// - branch: the branch to tag, or empty for the current branch
+// - cfg: the preflight configuration
+// returns:
+// - error: a *Failure if checks failed, otherwise an error object if something went wrong, or nil
+func Check(branch string, cfg config.PreflightConfig) error {
+	var failed []error
+
+	current, err := git.GetCurrentBranch()
+	if errors.Is(err, git.ErrDetachedHead) {
+		failed = append(failed, err)
+	} else if err != nil {
+		return err
+	}
+	if branch == "" {
+		branch = current
+	}
+
+	if cfg.CleanTree {
+		changes, err := git.UncommittedChanges()
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			failed = append(failed, fmt.Errorf("%w: %s", git.ErrDirtyTree, summarize(changes)))
+		}
+	}
+
+	if cfg.UpToDate && branch != "" {
+		upstream, behind, err := git.CommitsBehindUpstream(branch)
+		if err != nil {
+			return err
+		}
+		if behind > 0 {
+			failed = append(failed, fmt.Errorf("%w: %s is %d commit(s) behind %s, pull first", ErrBehindUpstream, branch, behind, upstream))
+		}
+	}
+
+	if cfg.NoOperation {
+		operation, err := git.InProgressOperation()
+		if err != nil {
+			return err
+		}
+		if operation != "" {
+			failed = append(failed, fmt.Errorf("%w: a %s is in progress, finish or abort it first", ErrOperationInProgress, operation))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return &Failure{Reasons: failed}
+}
+
+// Error lists the reasons of the failed checks.
+func (f *Failure) Error() string {
+	var msg strings.Builder
+	msg.WriteString(ErrFailed.Error() + " (use -force to tag anyway):")
+	for _, reason := range f.Reasons {
+		msg.WriteString("\n  - " + reason.Error())
+	}
+	return msg.String()
+}
+
+// Unwrap returns ErrFailed and the errors of the failed checks.
+func (f *Failure) Unwrap() []error {
+	return append([]error{ErrFailed}, f.Reasons...)
+}
+
+// ---------- Helper Functions ----------
+
+// summarize lists the first few changed files of git status output.
+func summarize(changes []string) string {
+	const shown = 3
+	files := make([]string, 0, shown)
+	for _, change := range changes[:min(shown, len(changes))] {
+		files = append(files, strings.TrimSpace(change[min(2, len(change)):]))
+	}
+	if len(changes) > shown {
+		return fmt.Sprintf("%s and %d more", strings.Join(files, ", "), len(changes)-shown)
+	}
+	return strings.Join(files, ", ")
+}
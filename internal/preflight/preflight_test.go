@@ -0,0 +1,47 @@
+package preflight
+
+import (
+	"errors"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/testutils"
+	"os"
+	"testing"
+)
+
+// TestCheck validates that each failed check is reported and that disabled checks are skipped.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestCheck(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	cfg := config.Default().Preflight
+
+	if err := Check("", cfg); err != nil {
+		t.Fatalf("Expected a clean repository to pass, got %v", err)
+	}
+
+	if err := os.WriteFile("README.md", []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify README.md: %v", err)
+	}
+	if err := os.WriteFile(".git/MERGE_HEAD", []byte("0000000000000000000000000000000000000000\n"), 0644); err != nil {
+		t.Fatalf("Failed to write MERGE_HEAD: %v", err)
+	}
+
+	err := Check("master", cfg)
+	for _, expected := range []error{ErrFailed, git.ErrDirtyTree, ErrOperationInProgress} {
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected %q to be reported, got %v", expected, err)
+		}
+	}
+	if errors.Is(err, ErrBehindUpstream) {
+		t.Errorf("Did not expect a branch without upstream to be behind, got %v", err)
+	}
+
+	if err := Check("release/1.x", config.PreflightConfig{}); err != nil {
+		t.Errorf("Expected disabled checks to be skipped, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Prompter asks the user questions on a terminal.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ---------- Prompt Functions ----------
+
+// New creates a Prompter reading answers from in and writing questions to out.
+// parameters:
+// - in: the reader of answers, usually os.Stdin
+// - out: the writer of questions, usually os.Stdout
+// returns:
+// - *Prompter: the prompter
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// IsTerminal reports whether a file is a terminal, i.e. whether a user can answer prompts on it.
+// parameters:
+// - f: the file, usually os.Stdin or os.Stdout
+// returns:
+// - bool: true if the file is a character device
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Printf writes a formatted message for the user.
+// parameters:
+// - format: the format string
+// - args: the arguments of the format string
+func (p *Prompter) Printf(format string, args ...any) {
+	fmt.Fprintf(p.out, format, args...)
+}
+
+// Ask asks a question and reads the answer.
+// parameters:
+// - question: the question, including any hint of the expected answers
+// returns:
+// - string: the answer without surrounding whitespace
+// - error: io.EOF if the input ended before an answer, otherwise an error object if reading failed, or nil
+func (p *Prompter) Ask(question string) (string, error) {
+	fmt.Fprint(p.out, question)
+	answer, err := p.in.ReadString('\n')
+	if err == io.EOF && answer != "" {
+		// the last answer lacks a newline
+		err = nil
+	}
+	if err == io.EOF {
+		fmt.Fprintln(p.out)
+		return "", err
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// Select lets the user choose one of several options by number, asking again after invalid answers.
+// parameters:
+// - title: the line printed above the options
+// - options: the options to choose from
+// - def: the index of the option chosen by an empty answer
+// returns:
+// - int: the index of the chosen option
+// - error: io.EOF if the input ended before an answer, otherwise an error object if reading failed, or nil
+func (p *Prompter) Select(title string, options []string, def int) (int, error) {
+	fmt.Fprintln(p.out, title)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "[%d] %s\n", i+1, option)
+	}
+
+	for {
+		answer, err := p.Ask(fmt.Sprintf("Enter a number (default %d): ", def+1))
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" {
+			return def, nil
+		}
+		choice, err := strconv.Atoi(answer)
+		if err == nil && choice >= 1 && choice <= len(options) {
+			return choice - 1, nil
+		}
+		fmt.Fprintf(p.out, "Invalid selection: %s\n", answer)
+	}
+}
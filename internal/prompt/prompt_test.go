@@ -0,0 +1,50 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestSelect validates that options are chosen by number or by default, and that invalid answers are asked again.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestSelect(t *testing.T) {
+	options := []string{"develop", "main", "release/1.x"}
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"by number", "3\n", 2},
+		{"default", "\n", 1},
+		{"after invalid answers", "0\nmain\n1\n", 0},
+		{"without trailing newline", "3", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			choice, err := New(strings.NewReader(tt.input), &out).Select("Select a branch:", options, 1)
+			if err != nil {
+				t.Fatalf("Select failed: %v", err)
+			}
+			if choice != tt.expected {
+				t.Errorf("Expected option %d, got %d", tt.expected, choice)
+			}
+			if !strings.Contains(out.String(), "[3] release/1.x") {
+				t.Errorf("Expected the options to be listed, got %q", out.String())
+			}
+		})
+	}
+
+	if _, err := New(strings.NewReader(""), io.Discard).Select("Select a branch:", options, 0); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF without input, got %v", err)
+	}
+}
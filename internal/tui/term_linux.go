@@ -0,0 +1,11 @@
+//go:build linux
+
+package tui
+
+import "syscall"
+
+// ioctl requests reading and writing the terminal attributes.
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
@@ -0,0 +1,29 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package tui
+
+import (
+	"errors"
+	"os"
+)
+
+// errUnsupported is returned where raw terminal mode is not implemented.
+var errUnsupported = errors.New("the terminal UI is not supported on this platform")
+
+// termState holds the terminal attributes to restore when leaving raw mode.
+type termState struct{}
+
+// makeRaw switches a terminal into raw mode; it is not supported on this platform.
+func makeRaw(f *os.File) (*termState, error) {
+	return nil, errUnsupported
+}
+
+// restore restores the terminal attributes saved by makeRaw.
+func restore(f *os.File, state *termState) error {
+	return nil
+}
+
+// size returns the number of columns and rows of a terminal.
+func size(f *os.File) (int, int, error) {
+	return 0, 0, errUnsupported
+}
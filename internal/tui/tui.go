@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Names of keys returned by ReadKey besides printable characters, which are returned as themselves.
+const (
+	KeyUp        = "up"
+	KeyDown      = "down"
+	KeyPageUp    = "pgup"
+	KeyPageDown  = "pgdown"
+	KeyHome      = "home"
+	KeyEnd       = "end"
+	KeyEnter     = "enter"
+	KeyEscape    = "esc"
+	KeyBackspace = "backspace"
+	KeyCtrlC     = "ctrl+c"
+)
+
+// ANSI escape sequences used to draw the screen.
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l" // switch to the alternate screen and hide the cursor
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l" // show the cursor and return to the normal screen
+	clearScreen    = "\x1b[H\x1b[2J"
+	Reverse        = "\x1b[7m" // starts reverse video, e.g. for the selected line
+	Bold           = "\x1b[1m" // starts bold text
+	Reset          = "\x1b[0m" // ends all text attributes
+)
+
+// escapeSequences maps the escape sequences sent by terminals to key names.
+var escapeSequences = map[string]string{
+	"[A": KeyUp, "OA": KeyUp,
+	"[B": KeyDown, "OB": KeyDown,
+	"[5~": KeyPageUp,
+	"[6~": KeyPageDown,
+	"[H":  KeyHome, "OH": KeyHome, "[1~": KeyHome,
+	"[F": KeyEnd, "OF": KeyEnd, "[4~": KeyEnd,
+}
+
+// Terminal is a full-screen terminal session in raw mode.
+type Terminal struct {
+	in    *os.File
+	out   *os.File
+	keys  *bufio.Reader
+	state *termState
+}
+
+// ---------- Terminal Functions ----------
+
+// Open switches a terminal to raw mode and the alternate screen. Close restores it.
+// parameters:
+// - in: the terminal input, usually os.Stdin
+// - out: the terminal output, usually os.Stdout
+// returns:
+// - *Terminal: the terminal session
+// - error: an error object if the input is not a terminal or raw mode is not supported, otherwise nil
+func Open(in, out *os.File) (*Terminal, error) {
+	state, err := makeRaw(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	fmt.Fprint(out, enterAltScreen)
+	return &Terminal{in: in, out: out, keys: bufio.NewReader(in), state: state}, nil
+}
+
+// Close returns to the normal screen and restores the terminal attributes.
+// returns:
+// - error: an error object if restoring the terminal failed, otherwise nil
+func (t *Terminal) Close() error {
+	fmt.Fprint(t.out, leaveAltScreen)
+	return restore(t.in, t.state)
+}
+
+// Size returns the number of columns and rows of the terminal, or 80x24 if they are unknown.
+// returns:
+// - int: the number of columns
+// - int: the number of rows
+func (t *Terminal) Size() (int, int) {
+	width, height, err := size(t.out)
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// ReadKey waits for the next key press.
+// returns:
+// - string: a key name such as KeyUp, or the typed character
+// - error: an error object if reading failed, otherwise nil
+func (t *Terminal) ReadKey() (string, error) {
+	return readKey(t.keys)
+}
+
+// Draw replaces the screen with the given lines, cutting them to the width of the terminal.
+// parameters:
+// - lines: the lines to draw, which may contain the attributes Reverse, Bold and Reset
+func (t *Terminal) Draw(lines []string) {
+	width, height := t.Size()
+	var screen strings.Builder
+	screen.WriteString(clearScreen)
+	for i, line := range lines[:min(len(lines), height)] {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		screen.WriteString(Truncate(line, width))
+	}
+	fmt.Fprint(t.out, screen.String())
+}
+
+// Truncate cuts a line to a number of visible characters, not counting text attributes.
+// parameters:
+// - line: the line, which may contain the attributes Reverse, Bold and Reset
+// - width: the maximum number of visible characters
+// returns:
+// - string: the cut line, ending with Reset if it contains attributes
+func Truncate(line string, width int) string {
+	var out strings.Builder
+	visible := 0
+	attributes := false
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			end := strings.IndexByte(line[i:], 'm')
+			if end < 0 {
+				break
+			}
+			out.WriteString(line[i : i+end+1])
+			attributes = true
+			i += end + 1
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(line[i:])
+		if visible == width {
+			break
+		}
+		if r == '\t' {
+			r = ' '
+		}
+		out.WriteRune(r)
+		visible++
+		i += n
+	}
+	if attributes {
+		out.WriteString(Reset)
+	}
+	return out.String()
+}
+
+// ---------- Helper Functions ----------
+
+// readKey reads a key press, decoding escape sequences of special keys.
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return KeyEnter, nil
+	case 0x7f, '\b':
+		return KeyBackspace, nil
+	case 0x03:
+		return KeyCtrlC, nil
+	case 0x1b:
+		// a lone escape is the escape key; terminals send the rest of a sequence at once
+		if r.Buffered() == 0 {
+			return KeyEscape, nil
+		}
+		seq := make([]byte, 0, 4)
+		for r.Buffered() > 0 && len(seq) < cap(seq) {
+			b, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq = append(seq, b)
+			if key, ok := escapeSequences[string(seq)]; ok {
+				return key, nil
+			}
+			if len(seq) > 1 && (b >= 'A' && b <= 'Z' || b == '~') {
+				break
+			}
+		}
+		return KeyEscape, nil
+	}
+	return string(c), nil
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestReadKey validates that escape sequences of special keys are decoded and other characters are returned as typed.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[Aa\r\x1b[6~\x7fé\x03"))
+	expected := []string{KeyUp, "a", KeyEnter, KeyPageDown, KeyBackspace, "é", KeyCtrlC}
+	for _, want := range expected {
+		key, err := readKey(r)
+		if err != nil {
+			t.Fatalf("readKey failed: %v", err)
+		}
+		if key != want {
+			t.Errorf("Expected %q, got %q", want, key)
+		}
+	}
+}
+
+// TestTruncate validates that lines are cut to their visible width, keeping text attributes.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		line     string
+		width    int
+		expected string
+	}{
+		{"hello world", 5, "hello"},
+		{"short", 10, "short"},
+		{Reverse + "selected" + Reset, 3, Reverse + "sel" + Reset},
+		{"a\tb", 3, "a b"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.line, tt.width); got != tt.expected {
+			t.Errorf("Truncate(%q, %d): expected %q, got %q", tt.line, tt.width, tt.expected, got)
+		}
+	}
+}
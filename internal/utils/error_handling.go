@@ -2,10 +2,16 @@ package utils
 
 import (
 	"fmt"
+	"git-tagger/internal/logging"
 	"os"
 	"strings"
 )
 
+const (
+	ExitFailure = 1 // exit code of errors without a more specific exit code
+	ExitUsage   = 2 // exit code of invalid command-line arguments
+)
+
 // ---------- Argument Handling Functions ----------
 
 // HandleUnparsedArgs handles any unparsed command-line arguments by printing an error message and exiting.
@@ -13,45 +19,40 @@ import (
 // - args: a slice of unparsed command-line arguments
 func HandleUnparsedArgs(args []string) {
 	if len(args) > 0 {
-		// Write to stderr and check for errors while doing so
-		if _, err := fmt.Fprintf(os.Stderr, "Incorrect flag(s) provided: %s\n", strings.Join(args, ", ")); err != nil {
-			_, err := fmt.Fprintf(os.Stderr, "Error occurred while writing to stderr: %v\n", err)
-			if err != nil {
-				return
-			}
-			os.Exit(2)
-		}
-
-		// Exit with a non-zero status indicating incorrect usage
-		os.Exit(1)
+		// Exit with the usage exit code even if stderr cannot be written
+		_, _ = fmt.Fprintf(os.Stderr, "Incorrect flag(s) provided: %s\n", strings.Join(args, ", "))
+		os.Exit(ExitUsage)
 	}
 }
 
-// WrapErrorf wraps an error with the given format and arguments if an error occurred.
+// WrapErrorf wraps an error with the given format if an error occurred. The format wraps the error
+// with a %w verb; formats without one get ": %w" appended.
 // Parameters:
-// - err: the original error
 // - format: the format string for wrapping the error
-// - args: additional arguments for formatting the error message
+// - err: the original error
 // Returns:
 // - an error wrapped with the specified message if it occurred, otherwise nil
 func WrapErrorf(format string, err error) error {
-	if err != nil {
-		// Correctly wrap the error with additional context
-		return fmt.Errorf(format+": %w", err)
+	if err == nil {
+		return nil
 	}
-	return nil
+	if !strings.Contains(format, "%w") {
+		format += ": %w"
+	}
+	return fmt.Errorf(format, err)
 }
 
-// LogAndExit logs the error message along with context and exits the program.
-func LogAndExit(context string, err error) {
-	// Print a formatted error message including the context and error details
+// LogAndExit logs the error message along with context and exits the program with the given exit code.
+// parameters:
+// - context: a description of what failed
+// - err: the error that occurred, or nil
+// - code: the exit code of the program
+func LogAndExit(context string, err error, code int) {
+	// Log the context together with the error details
 	if err != nil {
-		_, err := fmt.Fprintf(os.Stderr, "Error - %s: %v\n", context, err)
-		if err != nil {
-			return
-		}
+		logging.Logger().Error(context, "error", err)
 	} else {
-		_, _ = fmt.Fprintf(os.Stderr, "Error - %s\n", context)
+		logging.Logger().Error(context)
 	}
-	os.Exit(1)
+	os.Exit(code)
 }
@@ -2,11 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"git-tagger/internal/logging"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -188,55 +187,12 @@ func WriteFile(filePath, content string) error {
 // - filePath: the path of the file being closed, used for logging purposes
 func closeFile(file *os.File, filePath string) {
 	if err := file.Close(); err != nil {
-		fmt.Printf("warning: failed to close file %s: %v\n", filePath, err)
+		logging.Logger().Warn("Failed to close file "+filePath, "error", err)
 	}
 }
 
 // ---------- Utility Functions ----------
 
-// CompareSemVer compares two semantic version strings.
-// Parameters:
-// - a: the first semantic version string to compare (e.g., "v1.2.3").
-// - b: the second semantic version string to compare (e.g., "v2.0.1").
-// Returns:
-//   - int: an integer indicating the result of the comparison:
-//     -1 if version a is less than version b
-//     1 if version a is greater than version b
-//     0 if version a is equal to version b
-//
-// The function assumes the input versions follow the semantic versioning format (vX.Y.Z).
-// It will panic if the version strings do not have exactly three parts or if the parts
-// are not valid integers.
-func CompareSemVer(a, b string) int {
-	// Validate both tags as semantic versions
-	if !IsSemVer(a) || !IsSemVer(b) {
-		panic("Tags must be in semantic version format (vX.Y.Z)")
-	}
-
-	// Remove 'v' prefix from both tags
-	aParts := strings.Split(a[1:], ".")
-	bParts := strings.Split(b[1:], ".")
-
-	for i := 0; i < 3; i++ {
-		aNum, errA := strconv.Atoi(aParts[i])
-		bNum, errB := strconv.Atoi(bParts[i])
-
-		// Handle parsing errors
-		if errA != nil || errB != nil {
-			fmt.Printf("Skipping invalid version parts: %v, %v\n", aParts[i], bParts[i])
-			return 0
-		}
-
-		if aNum < bNum {
-			return -1
-		} else if aNum > bNum {
-			return 1
-		}
-	}
-
-	return 0
-}
-
 /* BuildExecutable builds the Go executable for the project.
 // - projectRoot: the root directory of the project where the `cmd/tagger/main.go` is located
 // - outputPath: the path to output the built binary
@@ -274,30 +230,6 @@ func FilterEmptyStrings(slice []string) []string {
 	return result
 }
 
-// IsSemVer checks if a given string is a semantic version
-// parameters:
-// - version: the version string to check
-// returns:
-// - bool: true if the string is a semantic version, otherwise false
-func IsSemVer(tag string) bool {
-	// Regex to strictly match semantic versions like vX.Y.Z where X, Y, and Z are integers
-	// It allows an optional hash suffix (e.g., v1.2.3-hash)
-	semVerPattern := `^v\d+\.\d+\.\d+(-[a-zA-Z0-9]+)?$`
-	match, _ := regexp.MatchString(semVerPattern, tag)
-	return match
-}
-
-// StripHashSuffix removes the hash suffix from a version tag if present.
-// parameters:
-// - tag: the version tag with potential hash suffix
-// returns:
-// - string: the core semantic version without hash
-func StripHashSuffix(tag string) string {
-	// Split by the dash to separate version and hash
-	parts := strings.SplitN(tag, "-", 2)
-	return parts[0] // Return the core semantic version without the hash
-}
-
 // StringSliceContains checks if a specific string is present in a slice of strings.
 func StringSliceContains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,284 @@
+package version
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/issues"
+	"git-tagger/internal/journal"
+	"git-tagger/internal/logging"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BootstrapOptions selects the baseline of a repository adopting git-tagger and the historical releases to
+// backfill before it.
+type BootstrapOptions struct {
+	Version string   // baseline version, with or without the tag prefix
+	Commit  string   // revision to tag with the baseline version
+	Merges  bool     // backfill a release at every merge on the first-parent history of the baseline
+	Points  []string // revisions to backfill releases at, which must be ancestors of the baseline
+}
+
+// ---------- Bootstrap Functions ----------
+
+// PlanBootstrap computes the release tags that adopt a repository without version tags: one for the
+// baseline commit and, optionally, one for each selected historical point. Backfilled versions are derived
+// from v0.0.0 with the highest bump among the commits each point shipped, and must stay below the baseline.
+// The history before the baseline is read with a single git query.
+// parameters:
+// - opts: the baseline and the points to backfill
+// - cfg: the repository configuration
+// returns:
+// - *Plan: the tags to create, the baseline last; points that shipped no release-worthy commit are skipped
+// - error: an error object if something went wrong, otherwise nil
+func PlanBootstrap(opts BootstrapOptions, cfg *config.Config) (*Plan, error) {
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := scheme.Parse(strings.TrimPrefix(opts.Version, cfg.Tag.Prefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline version %s: %w", opts.Version, err)
+	}
+	if semver, ok := baseline.(SemVer); ok && semver.IsHashBuild() {
+		return nil, fmt.Errorf("invalid baseline version %s: pre-release looks like a commit hash", opts.Version)
+	}
+	backfill := opts.Merges || len(opts.Points) > 0
+	if backfill && scheme.Name() != SchemeSemVer {
+		return nil, fmt.Errorf("backfilling releases is not supported with the %s versioning scheme", scheme.Name())
+	}
+
+	existing, err := releases(cfg, "", true)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("the repository is already versioned (latest %s); bootstrap only adopts repositories without version tags",
+			existing[len(existing)-1].Tag)
+	}
+
+	hash, err := git.ResolveCommit(opts.Commit)
+	if err != nil {
+		return nil, err
+	}
+	history, err := git.GetCommitsBetween("", hash)
+	if err != nil {
+		return nil, err
+	}
+	points, err := bootstrapPoints(hash, history, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	classifier, err := NewClassifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	extractor, err := issues.NewExtractor(cfg.Issues)
+	if err != nil {
+		return nil, err
+	}
+	// merged commits are shipped by the same release as their merge and classified themselves
+	unit := cfg.History
+	unit.MergeAsUnit = false
+
+	byHash := make(map[string]git.Commit, len(history))
+	for _, commit := range history {
+		byHash[commit.Hash] = commit
+	}
+	plan := &Plan{Branch: opts.Commit, BaseVersion: scheme.Initial(), dates: map[string]time.Time{}}
+	current := scheme.Initial()
+	shipped := map[string]bool{}
+	for _, point := range append(points, hash) {
+		commits := newlyReachable(point, byHash, shipped)
+		plan.commits = append(plan.commits, point)
+		plan.dates[point] = byHash[point].Date
+
+		var referenced []string
+		for _, commit := range commits {
+			for _, issue := range extractor.Extract(commit) {
+				if !slices.Contains(referenced, issue) {
+					referenced = append(referenced, issue)
+				}
+			}
+		}
+
+		if point == hash {
+			if len(plan.Tags) > 0 && scheme.Compare(baseline, current) <= 0 {
+				return nil, fmt.Errorf("baseline %s must be higher than the last backfilled version %s; choose a higher baseline or fewer points",
+					baseline.Tag(cfg.Tag.Prefix), current.Tag(cfg.Tag.Prefix))
+			}
+			plan.Tags = append(plan.Tags, PlannedTag{
+				Commit:  hash,
+				Message: byHash[hash].Subject,
+				Reason:  "baseline version",
+				Version: baseline,
+				Tag:     baseline.Tag(cfg.Tag.Prefix),
+				Issues:  referenced,
+			})
+			break
+		}
+
+		// A backfilled release bumps by the highest level among the commits it shipped
+		level, reason := "none", fmt.Sprintf("none of %d commit(s) warrants a release", len(commits))
+		for _, commit := range commits {
+			class, err := ClassifyUnit(commit, classifier, unit)
+			if err != nil {
+				return nil, err
+			}
+			// a merge without a recognized message is judged by its merged commits
+			if len(commit.Parents) > 1 && class.Rule == "" {
+				continue
+			}
+			if levelRank(class.Level) > levelRank(level) {
+				level, reason = class.Level, fmt.Sprintf("highest of %d commit(s): %s in %.7s", len(commits), class, commit.Hash)
+			}
+		}
+		if semver, ok := current.(SemVer); ok && level != "none" {
+			adjusted, err := InitialDevelopmentLevel(semver, level, cfg.Versioning.ZeroPolicy)
+			if err != nil {
+				return nil, err
+			}
+			if adjusted != level {
+				reason += fmt.Sprintf(", %s as %s before 1.0.0", level, adjusted)
+				level = adjusted
+			}
+		}
+
+		planned := PlannedTag{Commit: point, Message: byHash[point].Subject, Level: level, Reason: reason, Issues: referenced}
+		if level == "none" {
+			plan.Skipped = append(plan.Skipped, planned)
+			continue
+		}
+		if current, err = scheme.Next(current, level); err != nil {
+			return nil, fmt.Errorf("failed to increment version for commit %s: %w", point, err)
+		}
+		planned.Version, planned.Tag = current, current.Tag(cfg.Tag.Prefix)
+		plan.Tags = append(plan.Tags, planned)
+	}
+	return plan, nil
+}
+
+// ApplyBootstrap creates the release tags of a bootstrap plan while holding the repository lock, dated
+// like the commits they tag and recorded in a run journal. If creating a tag fails, the tags already
+// created are rolled back.
+// parameters:
+// - plan: the plan computed by PlanBootstrap
+// - cfg: the repository configuration
+// returns:
+// - string: the ID of the run journal, for undo
+// - error: an error object if something went wrong, otherwise nil
+func ApplyBootstrap(plan *Plan, cfg *config.Config) (string, error) {
+	var runID string
+	err := withLock(cfg, func() error {
+		for _, planned := range plan.Tags {
+			if git.TagExists(planned.Tag) {
+				return fmt.Errorf("%w: %s", git.ErrTagExists, planned.Tag)
+			}
+		}
+
+		branch, err := runBranch()
+		if err != nil {
+			return err
+		}
+		run, err := journal.Start(branch)
+		if err != nil {
+			return fmt.Errorf("failed to start run journal: %w", err)
+		}
+		opts := git.TagOptions{Sign: cfg.Tag.Sign}
+		for i, planned := range plan.Tags {
+			logging.Logger().Info("Tagging commit", "commit", planned.Commit, "tag", planned.Tag)
+
+			message := fmt.Sprintf("Release %s (backfilled)", planned.Tag)
+			if i == len(plan.Tags)-1 {
+				message = fmt.Sprintf("Release %s (baseline)", planned.Tag)
+			}
+			opts.Date = plan.dates[planned.Commit]
+			if err := run.Record(planned.Tag, planned.Commit); err != nil {
+				return rollbackRun(run, err)
+			}
+			if err := git.CreateTagWithOptions(planned.Tag, annotation(message, planned.Issues), planned.Commit, opts); err != nil {
+				return rollbackRun(run, fmt.Errorf("failed to create tag %s for commit %s: %w", planned.Tag, planned.Commit, err))
+			}
+			if err := run.Confirm(planned.Tag); err != nil {
+				return rollbackRun(run, err)
+			}
+		}
+		if err := pushRun(run, cfg); err != nil {
+			return err
+		}
+		if err := run.Finish(journal.StatusCompleted); err != nil {
+			return err
+		}
+		runID = run.RunID
+		return nil
+	})
+	return runID, err
+}
+
+// ---------- Helper Functions ----------
+
+// bootstrapPoints resolves the points to backfill releases at, ordered from the oldest to the newest.
+// parameters:
+// - baseline: the full hash of the baseline commit
+// - history: the commits reachable from the baseline, parents before their children
+// - opts: the selected points
+// returns:
+// - []string: the full hashes of the points, without the baseline
+// - error: an error object if a point is not an ancestor of the baseline, otherwise nil
+func bootstrapPoints(baseline string, history []git.Commit, opts BootstrapOptions) ([]string, error) {
+	position := make(map[string]int, len(history))
+	parents := make(map[string][]string, len(history))
+	for i, commit := range history {
+		position[commit.Hash] = i
+		parents[commit.Hash] = commit.Parents
+	}
+
+	selected := map[string]bool{}
+	if opts.Merges {
+		for hash := baseline; len(parents[hash]) > 0; hash = parents[hash][0] {
+			if len(parents[hash]) > 1 && hash != baseline {
+				selected[hash] = true
+			}
+		}
+	}
+	for _, point := range opts.Points {
+		hash, err := git.ResolveCommit(point)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := position[hash]; !ok {
+			return nil, fmt.Errorf("cannot backfill a release at %s: it is not an ancestor of the baseline %.7s", point, baseline)
+		}
+		if hash != baseline {
+			selected[hash] = true
+		}
+	}
+
+	points := make([]string, 0, len(selected))
+	for hash := range selected {
+		points = append(points, hash)
+	}
+	slices.SortFunc(points, func(a, b string) int { return position[a] - position[b] })
+	return points, nil
+}
+
+// newlyReachable collects the commits reachable from a commit that are not yet marked as seen, and marks them.
+func newlyReachable(hash string, byHash map[string]git.Commit, seen map[string]bool) []git.Commit {
+	var reached []git.Commit
+	pending := []string{hash}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		commit, ok := byHash[next]
+		if !ok || seen[next] {
+			continue
+		}
+		seen[next] = true
+		reached = append(reached, commit)
+		pending = append(pending, commit.Parents...)
+	}
+	return reached
+}
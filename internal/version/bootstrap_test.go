@@ -0,0 +1,90 @@
+package version
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/testutils"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestBootstrap validates that bootstrapping tags the baseline and backfills releases at the merges before
+// it, dated like their commits, and that later tagging runs start from the baseline.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestBootstrap(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"checkout", "-q", "-b", "feature"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: search"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: search results"},
+		{"checkout", "-q", "-"},
+		{"merge", "-q", "--no-ff", "feature", "-m", "Merge branch feature"},
+		{"checkout", "-q", "-b", "docs"},
+		{"commit", "-q", "--allow-empty", "-m", "docs: usage"},
+		{"checkout", "-q", "-"},
+		{"merge", "-q", "--no-ff", "docs", "-m", "Merge branch docs"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: crash"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	cfg := config.Default()
+
+	if _, err := PlanBootstrap(BootstrapOptions{Version: "0.1.0", Commit: "HEAD", Merges: true}, cfg); err == nil {
+		t.Errorf("Expected a baseline not above the backfilled v0.1.0 to be rejected")
+	}
+
+	plan, err := PlanBootstrap(BootstrapOptions{Version: "v1.0.0", Commit: "HEAD", Merges: true}, cfg)
+	if err != nil {
+		t.Fatalf("PlanBootstrap failed: %v", err)
+	}
+	var tags []string
+	for _, planned := range plan.Tags {
+		tags = append(tags, planned.Tag+" "+planned.Message)
+	}
+	expected := []string{"v0.1.0 Merge branch feature", "v1.0.0 fix: crash"}
+	if !slices.Equal(tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Message != "Merge branch docs" {
+		t.Errorf("Expected the documentation merge to be skipped, got %+v", plan.Skipped)
+	}
+
+	if _, err := ApplyBootstrap(plan, cfg); err != nil {
+		t.Fatalf("ApplyBootstrap failed: %v", err)
+	}
+	releases, err := ListReleases(cfg, false)
+	if err != nil || len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %+v (%v)", releases, err)
+	}
+	merge, err := time.Parse(time.RFC3339, testutils.RunGitCommandAndGetOutput(t, "log", "-1", "--format=%cI", "HEAD~2"))
+	if err != nil || !releases[0].Date.Equal(merge) {
+		t.Errorf("Expected the backfilled release to be dated %s like its commit, got %s (%v)", merge, releases[0].Date, err)
+	}
+
+	if err := testutils.RunGitCommand("commit", "-q", "--allow-empty", "-m", "feat: export"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+	tagged, err := versionCommits(cfg)
+	if err != nil {
+		t.Fatalf("versionCommits failed: %v", err)
+	}
+	if untagged, err := git.FindUntagged("HEAD", tagged, false); err != nil || len(untagged) != 1 {
+		t.Errorf("Expected only the commit after the baseline to be untagged, got %v (%v)", untagged, err)
+	}
+	next, err := PlanUntaggedCommits("HEAD", cfg)
+	if err != nil || next.Base != "v1.0.0" || len(next.Tags) != 1 || next.Tags[0].Version.String() != "1.1.0" {
+		t.Errorf("Expected the next run to tag 1.1.0 from v1.0.0, got %+v (%v)", next, err)
+	}
+
+	if _, err := PlanBootstrap(BootstrapOptions{Version: "2.0.0", Commit: "HEAD"}, cfg); err == nil {
+		t.Errorf("Expected bootstrapping a versioned repository to be rejected")
+	}
+}
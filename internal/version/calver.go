@@ -0,0 +1,301 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calVerTokens are the supported format tokens (https://calver.org), longest first so that
+// tokenizing prefers YYYY over YY.
+var calVerTokens = []struct {
+	token   string
+	pattern string
+}{
+	{"YYYY", `(\d{4})`},
+	{"MICRO", `(\d+)`},
+	{"YY", `(\d{1,3})`},
+	{"0Y", `(\d{2,3})`},
+	{"MM", `(\d{1,2})`},
+	{"0M", `(\d{2})`},
+	{"WW", `(\d{1,2})`},
+	{"0W", `(\d{2})`},
+	{"DD", `(\d{1,2})`},
+	{"0D", `(\d{2})`},
+}
+
+// CalVer is a calendar version. Parts not used by its format are zero.
+type CalVer struct {
+	Year  int
+	Month int
+	Week  int
+	Day   int
+	Micro int
+	Build string // commit hash of a build tag, if any
+
+	format []string // tokens and literal separators of the format
+}
+
+// CalVerScheme is the calendar versioning scheme. The micro counter increments for every
+// release within a period and resets when the period (e.g. the month) changes.
+type CalVerScheme struct {
+	Format string           // the version format, e.g. YYYY.0M.MICRO
+	Now    func() time.Time // the clock used to determine the current period
+
+	parts   []string
+	pattern *regexp.Regexp
+}
+
+// ---------- CalVer Functions ----------
+
+// NewCalVerScheme creates a calendar versioning scheme for a format such as YYYY.0M.MICRO or YY.MM.MICRO.
+// parameters:
+// - format: the version format built from YYYY, YY, 0Y, MM, 0M, WW, 0W, DD, 0D and MICRO
+// - now: the clock used to determine the current period, or nil for time.Now
+// returns:
+// - *CalVerScheme: the scheme
+// - error: an error object if the format is invalid, otherwise nil
+func NewCalVerScheme(format string, now func() time.Time) (*CalVerScheme, error) {
+	if format == "" {
+		format = "YYYY.0M.MICRO"
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	s := &CalVerScheme{Format: format, Now: now}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	hasDate := false
+	for rest := format; rest != ""; {
+		token := ""
+		for _, t := range calVerTokens {
+			if strings.HasPrefix(rest, t.token) {
+				token = t.token
+				pattern.WriteString(t.pattern)
+				break
+			}
+		}
+		if token == "" {
+			// literal separator
+			token = rest[:1]
+			pattern.WriteString(regexp.QuoteMeta(token))
+		} else if token != "MICRO" {
+			hasDate = true
+		}
+		s.parts = append(s.parts, token)
+		rest = rest[len(token):]
+	}
+	pattern.WriteString("$")
+
+	if !hasDate {
+		return nil, fmt.Errorf("invalid calver format %q: no date token (YYYY, YY, 0Y, MM, 0M, WW, 0W, DD, 0D)", format)
+	}
+	s.pattern = regexp.MustCompile(pattern.String())
+	return s, nil
+}
+
+// Name returns the name of the scheme.
+func (s *CalVerScheme) Name() string { return SchemeCalVer }
+
+// Parse parses a calendar version without prefix.
+func (s *CalVerScheme) Parse(raw string) (Version, error) {
+	m := s.pattern.FindStringSubmatch(raw)
+	if m == nil {
+		return nil, fmt.Errorf("invalid version format: %s (expected %s)", raw, s.Format)
+	}
+
+	v := CalVer{format: s.parts}
+	group := 1
+	for _, part := range s.parts {
+		if !isCalVerToken(part) {
+			continue
+		}
+		n, _ := strconv.Atoi(m[group])
+		group++
+		// only the micro counter starts at 0; years of two or three digits count from 2000
+		if n < 1 && part != "MICRO" && part != "YY" && part != "0Y" {
+			return nil, fmt.Errorf("invalid version format: %s (%s must be at least 1)", raw, part)
+		}
+
+		switch part {
+		case "YYYY":
+			v.Year = n
+		case "YY", "0Y":
+			v.Year = 2000 + n
+		case "MM", "0M":
+			v.Month = n
+		case "WW", "0W":
+			v.Week = n
+		case "DD", "0D":
+			v.Day = n
+		case "MICRO":
+			v.Micro = n
+		}
+	}
+
+	if v.Month > 12 || v.Week > 53 || v.Day > 31 {
+		return nil, fmt.Errorf("invalid version format: %s (date out of range)", raw)
+	}
+	return v, nil
+}
+
+// Compare orders two calendar versions by date, then by micro counter.
+func (s *CalVerScheme) Compare(a, b Version) int {
+	av, bv := a.(CalVer), b.(CalVer)
+	for _, pair := range [][2]int{{av.Year, bv.Year}, {av.Month, bv.Month}, {av.Week, bv.Week}, {av.Day, bv.Day}, {av.Micro, bv.Micro}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Initial returns an empty version that precedes every calendar period.
+func (s *CalVerScheme) Initial() Version { return CalVer{format: s.parts} }
+
+// Next returns the version of the current period. Within the same period as the current
+// version the micro counter is incremented; in a new period it starts again at 0.
+// The bump level does not affect calendar versions.
+func (s *CalVerScheme) Next(current Version, _ string) (Version, error) {
+	cur := current.(CalVer)
+
+	next := s.period(s.Now())
+	switch c := s.Compare(next, cur.period()); {
+	case c > 0:
+		return next, nil
+	case !s.hasMicro():
+		return nil, fmt.Errorf("version %s already released for this period and format %s has no MICRO token", cur, s.Format)
+	default:
+		// same period, or the clock is behind the latest version: never go backwards
+		next = cur.period()
+		next.Micro = cur.Micro + 1
+		return next, nil
+	}
+}
+
+// String formats the version without a prefix.
+func (v CalVer) String() string {
+	var out strings.Builder
+	for _, part := range v.format {
+		switch part {
+		case "YYYY":
+			out.WriteString(strconv.Itoa(v.Year))
+		case "YY":
+			out.WriteString(strconv.Itoa(v.Year - 2000))
+		case "0Y":
+			fmt.Fprintf(&out, "%02d", v.Year-2000)
+		case "MM":
+			out.WriteString(strconv.Itoa(v.Month))
+		case "0M":
+			fmt.Fprintf(&out, "%02d", v.Month)
+		case "WW":
+			out.WriteString(strconv.Itoa(v.Week))
+		case "0W":
+			fmt.Fprintf(&out, "%02d", v.Week)
+		case "DD":
+			out.WriteString(strconv.Itoa(v.Day))
+		case "0D":
+			fmt.Fprintf(&out, "%02d", v.Day)
+		case "MICRO":
+			out.WriteString(strconv.Itoa(v.Micro))
+		default:
+			out.WriteString(part)
+		}
+	}
+	if v.Build != "" {
+		out.WriteString("+" + v.Build)
+	}
+	return out.String()
+}
+
+// Tag formats the version as a tag name with the given prefix.
+func (v CalVer) Tag(prefix string) string {
+	return prefix + v.String()
+}
+
+// Fields returns the parts of the version for output templates. Major, Minor and Patch
+// refer to the first three numeric components of the format.
+func (v CalVer) Fields() map[string]any {
+	fields := map[string]any{"Year": v.Year, "Month": v.Month, "Week": v.Week, "Day": v.Day, "Micro": v.Micro, "Pre": "", "Build": v.Build}
+
+	names := []string{"Major", "Minor", "Patch"}
+	for _, part := range v.format {
+		if len(names) == 0 {
+			break
+		}
+		if isCalVerToken(part) {
+			field := v
+			field.format, field.Build = []string{part}, ""
+			n, _ := strconv.Atoi(field.String())
+			fields[names[0]] = n
+			names = names[1:]
+		}
+	}
+	for _, name := range names {
+		fields[name] = 0
+	}
+	return fields
+}
+
+// withBuild returns the version with the given build hash.
+func (v CalVer) withBuild(hash string) Version {
+	v.Build = hash
+	return v
+}
+
+// ---------- Helper Functions ----------
+
+// period returns the version of the period containing t, with the micro counter at 0.
+func (s *CalVerScheme) period(t time.Time) CalVer {
+	v := CalVer{format: s.parts}
+	year, week := t.ISOWeek()
+	for _, part := range s.parts {
+		switch part {
+		case "YYYY", "YY", "0Y":
+			v.Year = t.Year()
+		case "MM", "0M":
+			v.Month = int(t.Month())
+		case "WW", "0W":
+			v.Week = week
+		case "DD", "0D":
+			v.Day = t.Day()
+		}
+	}
+	if v.Week != 0 && v.Year != 0 {
+		// weeks belong to ISO years, which differ from calendar years around New Year
+		v.Year = year
+	}
+	return v
+}
+
+// period returns the version's period, with the micro counter and build reset.
+func (v CalVer) period() CalVer {
+	v.Micro, v.Build = 0, ""
+	return v
+}
+
+// hasMicro reports whether the format contains a MICRO counter.
+func (s *CalVerScheme) hasMicro() bool {
+	for _, part := range s.parts {
+		if part == "MICRO" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCalVerToken reports whether a format part is a token rather than a literal separator.
+func isCalVerToken(part string) bool {
+	for _, t := range calVerTokens {
+		if t.token == part {
+			return true
+		}
+	}
+	return false
+}
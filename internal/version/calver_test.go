@@ -0,0 +1,110 @@
+package version
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalVerNext validates that the micro counter increments within a period and resets when the period changes.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestCalVerNext(t *testing.T) {
+	tests := []struct {
+		format   string
+		current  string
+		now      time.Time
+		expected string
+	}{
+		{"YYYY.0M.MICRO", "", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), "2026.03.0"},
+		{"YYYY.0M.MICRO", "2026.03.0", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), "2026.03.1"},
+		{"YYYY.0M.MICRO", "2026.03.4", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), "2026.04.0"},
+		{"YYYY.0M.MICRO", "2026.04.2", time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), "2026.04.3"},
+		{"YY.MM.MICRO", "26.12.7", time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC), "27.1.0"},
+		{"YYYY.0W.MICRO", "2026.52.1", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), "2026.53.0"},
+		{"YYYY.MM.DD", "2026.3.13", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), "2026.3.14"},
+	}
+
+	for _, tt := range tests {
+		scheme, err := NewCalVerScheme(tt.format, func() time.Time { return tt.now })
+		if err != nil {
+			t.Fatalf("NewCalVerScheme(%s) failed: %v", tt.format, err)
+		}
+
+		current := scheme.Initial()
+		if tt.current != "" {
+			if current, err = scheme.Parse(tt.current); err != nil {
+				t.Fatalf("Parse(%s) failed: %v", tt.current, err)
+			}
+		}
+
+		next, err := scheme.Next(current, "patch")
+		if err != nil {
+			t.Fatalf("Next(%s) failed: %v", tt.current, err)
+		}
+		if got := next.String(); got != tt.expected {
+			t.Errorf("Next(%s) with %s at %s: expected %s, got %s", tt.current, tt.format, tt.now.Format(time.DateOnly), tt.expected, got)
+		}
+	}
+
+	// Without a micro counter, a second release in the same period is impossible
+	scheme, _ := NewCalVerScheme("YYYY.MM.DD", func() time.Time { return time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC) })
+	current, _ := scheme.Parse("2026.3.14")
+	if _, err := scheme.Next(current, "patch"); err == nil {
+		t.Errorf("Expected an error for a second release on the same day without MICRO")
+	}
+}
+
+// TestCalVerParse validates parsing, ordering and template fields of calendar versions and build tags.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestCalVerParse(t *testing.T) {
+	scheme, err := NewCalVerScheme("YYYY.0M.MICRO", nil)
+	if err != nil {
+		t.Fatalf("NewCalVerScheme failed: %v", err)
+	}
+
+	tag, latest, found := highestVersion(scheme, []string{"v2026.03.1", "v2026.03.2-1a2b3c4", "v2025.12.9", "v1.2.3", "v2026.13.0"}, "v")
+	if !found || tag != "v2026.03.2-1a2b3c4" || latest.String() != "2026.03.2" {
+		t.Errorf("Expected v2026.03.2-1a2b3c4 (2026.03.2) as highest version, got %s (%v)", tag, latest)
+	}
+
+	fields := latest.Fields()
+	if fields["Major"] != 2026 || fields["Minor"] != 3 || fields["Patch"] != 2 || fields["Micro"] != 2 {
+		t.Errorf("Unexpected fields for %s: %v", latest, fields)
+	}
+
+	tests := []struct {
+		format string
+		raw    string
+		valid  bool
+	}{
+		{"YYYY.0M.MICRO", "2026.00.1", false},
+		{"YYYY.0M.MICRO", "0000.01.1", false},
+		{"YYYY.0W.MICRO", "2026.00.0", false},
+		{"YYYY.0M.0D", "2026.01.00", false},
+		{"YY.MM.DD", "26.0.1", false},
+		{"YYYY.0M.MICRO", "2026.01.0", true}, // the micro counter starts at 0
+		{"YY.MM.MICRO", "0.1.0", true},       // the year 2000
+	}
+	for _, tt := range tests {
+		scheme, err := NewCalVerScheme(tt.format, nil)
+		if err != nil {
+			t.Fatalf("NewCalVerScheme failed: %v", err)
+		}
+		if _, err := scheme.Parse(tt.raw); (err == nil) != tt.valid {
+			t.Errorf("Parse(%s) with %s: expected valid=%v, got %v", tt.raw, tt.format, tt.valid, err)
+		}
+	}
+
+	if _, err := NewCalVerScheme("MICRO", nil); err == nil {
+		t.Errorf("Expected an error for a format without date tokens")
+	}
+}
@@ -0,0 +1,199 @@
+package version
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"regexp"
+	"strings"
+)
+
+var (
+	// breakingRules are the built-in rules detecting breaking changes, evaluated after any configured rules.
+	breakingRules = []config.Rule{
+		{Name: "breaking-change", Field: "message", Pattern: `BREAKING[ -]CHANGE`, Level: "major"},
+		{Name: "breaking-bang", Field: "subject", Pattern: `^\w+(\([^)]*\))?!:`, Level: "major"},
+	}
+
+	mergePRPattern  = regexp.MustCompile(`^Merge pull request #\d+ from `)
+	squashPRPattern = regexp.MustCompile(`^(.+?) \(#\d+\)$`)
+
+	// typeRules are the built-in Conventional Commits type rules, evaluated after the skipped types.
+	typeRules = []config.Rule{
+		{Name: "feat", Field: "subject", Pattern: `^feat`, Level: "minor"},
+		{Name: "fix", Field: "subject", Pattern: `^fix`, Level: "patch"},
+	}
+)
+
+// Classification is the result of classifying a commit.
+type Classification struct {
+	Level string // level of version increment (major, minor, patch, none)
+	Rule  string // name of the matching rule, empty if the default level was used
+	Field string // part of the message the rule matched
+	Match string // text matched by the rule
+	Via   string // how the classified message was derived, e.g. from a pull request title
+}
+
+// Classifier determines the version increment level of commits from an ordered list of rules.
+type Classifier struct {
+	rules        []compiledRule
+	defaultLevel string
+}
+
+// compiledRule is a rule with its pattern compiled.
+type compiledRule struct {
+	config.Rule
+	re *regexp.Regexp
+}
+
+// ---------- Classifier Functions ----------
+
+// NewClassifier compiles the classification rules. They are evaluated in this order:
+// skip markers, configured rules, built-in breaking change rules, skipped commit types
+// and the built-in feat/fix rules. The built-in rules can be disabled in the configuration.
+// parameters:
+// - cfg: the repository configuration
+// returns:
+// - *Classifier: the compiled classifier
+// - error: an error object if a rule is invalid, otherwise nil
+func NewClassifier(cfg *config.Config) (*Classifier, error) {
+	var rules []config.Rule
+	if markers := cfg.Commits.SkipMarkers; len(markers) > 0 {
+		quoted := make([]string, len(markers))
+		for i, marker := range markers {
+			quoted[i] = regexp.QuoteMeta(marker)
+		}
+		rules = append(rules, config.Rule{Name: "skip-marker", Field: "message", Pattern: `(?i)` + strings.Join(quoted, "|"), Level: "none"})
+	}
+
+	rules = append(rules, cfg.Commits.Rules...)
+	if !cfg.Commits.DisableDefaults {
+		rules = append(rules, breakingRules...)
+	}
+
+	if types := cfg.Commits.SkipTypes; len(types) > 0 {
+		quoted := make([]string, len(types))
+		for i, commitType := range types {
+			quoted[i] = regexp.QuoteMeta(commitType)
+		}
+		rules = append(rules, config.Rule{Name: "skip-type", Field: "subject", Pattern: `^(` + strings.Join(quoted, "|") + `)(\([^)]*\))?:`, Level: "none"})
+	}
+
+	if !cfg.Commits.DisableDefaults {
+		rules = append(rules, typeRules...)
+	}
+
+	c := &Classifier{defaultLevel: cfg.Tag.IncrementLevel}
+	if c.defaultLevel == "" {
+		c.defaultLevel = "patch"
+	}
+	if !isLevel(c.defaultLevel) {
+		return nil, fmt.Errorf("invalid increment_level %q: expected major, minor, patch or none", c.defaultLevel)
+	}
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if rule.Field == "" {
+			rule.Field = "subject"
+		}
+		switch rule.Field {
+		case "subject", "body", "trailers", "message":
+		default:
+			return nil, fmt.Errorf("rule %q: unknown field %q: expected subject, body, trailers or message", rule.Name, rule.Field)
+		}
+		if !isLevel(rule.Level) {
+			return nil, fmt.Errorf("rule %q: invalid level %q: expected major, minor, patch or none", rule.Name, rule.Level)
+		}
+
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid pattern: %w", rule.Name, err)
+		}
+		c.rules = append(c.rules, compiledRule{Rule: rule, re: re})
+	}
+	return c, nil
+}
+
+// Classify determines the level of version increment of a commit using the first matching rule.
+// parameters:
+// - commit: the commit to classify
+// returns:
+// - Classification: the level and the rule that produced it
+func (c *Classifier) Classify(commit git.Commit) Classification {
+	for _, rule := range c.rules {
+		text := commitField(commit, rule.Field)
+		if loc := rule.re.FindStringIndex(text); loc != nil {
+			return Classification{Level: rule.Level, Rule: rule.Name, Field: rule.Field, Match: text[loc[0]:loc[1]]}
+		}
+	}
+	return Classification{Level: c.defaultLevel}
+}
+
+// String explains the classification in a human-readable form.
+func (c Classification) String() string {
+	explanation := "no rule matched, default level"
+	if c.Rule != "" {
+		explanation = fmt.Sprintf("rule %q matched %q in %s", c.Rule, c.Match, c.Field)
+	}
+	if c.Via != "" {
+		explanation += ", via " + c.Via
+	}
+	return fmt.Sprintf("%s (%s)", c.Level, explanation)
+}
+
+// ---------- Helper Functions ----------
+
+// commitField returns the part of a commit message a rule matches against.
+func commitField(commit git.Commit, field string) string {
+	switch field {
+	case "body":
+		return commit.Body
+	case "trailers":
+		return strings.Join(commit.Trailers, "\n")
+	case "message":
+		if commit.Body == "" {
+			return commit.Subject
+		}
+		return commit.Subject + "\n\n" + commit.Body
+	default:
+		return commit.Subject
+	}
+}
+
+// pullRequestTitle extracts the pull request title from a GitHub merge commit
+// ("Merge pull request #12 from ..." with the title as body) or a squash merge ("Title (#12)").
+func pullRequestTitle(commit git.Commit) (string, bool) {
+	if mergePRPattern.MatchString(commit.Subject) {
+		title, _, _ := strings.Cut(commit.Body, "\n")
+		title = strings.TrimSpace(title)
+		return title, title != ""
+	}
+	if m := squashPRPattern.FindStringSubmatch(commit.Subject); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// levelRank orders levels of version increment from none (0) to major (3).
+func levelRank(level string) int {
+	switch level {
+	case "major":
+		return 3
+	case "minor":
+		return 2
+	case "patch":
+		return 1
+	}
+	return 0
+}
+
+// isLevel reports whether a string is a valid level of version increment.
+func isLevel(level string) bool {
+	switch level {
+	case "major", "minor", "patch", "none":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package version
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"testing"
+)
+
+// TestClassify validates that configured rules are evaluated in order before the built-in
+// Conventional Commits rules, that skipped types and markers yield no bump, and that
+// unmatched commits fall back to the default level.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestClassify(t *testing.T) {
+	cfg := config.Default()
+	cfg.Commits.Rules = []config.Rule{
+		{Name: "jira-feature", Pattern: `^\[[A-Z]+-\d+\] Add`, Level: "minor"},
+		{Name: "gitmoji-boom", Pattern: `^(:boom:|💥)`, Level: "major"},
+		{Name: "internal", Field: "trailers", Pattern: `(?m)^Release-Note: none$`, Level: "none"},
+	}
+
+	classifier, err := NewClassifier(cfg)
+	if err != nil {
+		t.Fatalf("NewClassifier failed: %v", err)
+	}
+
+	tests := []struct {
+		commit git.Commit
+		level  string
+		rule   string
+	}{
+		{git.Commit{Subject: "[PROJ-123] Add endpoint"}, "minor", "jira-feature"},
+		{git.Commit{Subject: "[PROJ-124] Tweak endpoint"}, "patch", ""},
+		{git.Commit{Subject: ":boom: drop v1 API"}, "major", "gitmoji-boom"},
+		{git.Commit{Subject: "fix: typo", Trailers: []string{"Release-Note: none"}}, "none", "internal"},
+		{git.Commit{Subject: "feat(api)!: remove field"}, "major", "breaking-bang"},
+		{git.Commit{Subject: "feat: thing", Body: "BREAKING CHANGE: gone"}, "major", "breaking-change"},
+		{git.Commit{Subject: "feat: thing"}, "minor", "feat"},
+		{git.Commit{Subject: "fix: thing"}, "patch", "fix"},
+		{git.Commit{Subject: "docs(readme): typo"}, "none", "skip-type"},
+		{git.Commit{Subject: "chore: bump deps"}, "none", "skip-type"},
+		{git.Commit{Subject: "feat: hidden", Body: "[Skip Release]"}, "none", "skip-marker"},
+		{git.Commit{Subject: "docs!: drop old guide"}, "major", "breaking-bang"},
+	}
+
+	for _, tt := range tests {
+		got := classifier.Classify(tt.commit)
+		if got.Level != tt.level || got.Rule != tt.rule {
+			t.Errorf("Classify(%q): expected %s via %q, got %s via %q", tt.commit.Subject, tt.level, tt.rule, got.Level, got.Rule)
+		}
+	}
+
+	cfg.Commits.Rules = []config.Rule{{Pattern: `(`, Level: "minor"}}
+	if _, err := NewClassifier(cfg); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
+
+// TestPullRequestTitle validates extracting pull request titles from merge and squash commits.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestPullRequestTitle(t *testing.T) {
+	tests := []struct {
+		commit git.Commit
+		title  string
+		found  bool
+	}{
+		{git.Commit{Subject: "Merge pull request #5 from org/feature", Body: "feat: add thing\n\nmore text"}, "feat: add thing", true},
+		{git.Commit{Subject: "feat(api): add endpoint (#42)", Body: "* fix: wip\n* feat: wip"}, "feat(api): add endpoint", true},
+		{git.Commit{Subject: "Merge branch 'feature'"}, "", false},
+		{git.Commit{Subject: "fix: plain commit"}, "", false},
+	}
+
+	for _, tt := range tests {
+		title, found := pullRequestTitle(tt.commit)
+		if title != tt.title || found != tt.found {
+			t.Errorf("pullRequestTitle(%q): expected (%q, %v), got (%q, %v)", tt.commit.Subject, tt.title, tt.found, title, found)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package version
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/issues"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Channels of releases besides the pre-release identifiers (e.g. rc or beta).
+const (
+	ChannelStable     = "stable"      // releases without a pre-release part
+	ChannelPrerelease = "pre-release" // pre-releases whose first identifier is numeric, e.g. 1.0.0-1
+	ChannelBuild      = "build"       // hash-suffixed build tags
+)
+
+// Release is a version tag of the repository.
+type Release struct {
+	Tag     string    // name of the tag
+	Version Version   // version of the tag, without the hash suffix of build tags
+	Commit  string    // full hash of the tagged commit
+	Date    time.Time // creation date of the tag
+	Channel string    // ChannelStable, ChannelBuild, or the first pre-release identifier, e.g. rc
+}
+
+// LoggedCommit is a commit of a revision range with its classification and the version tags pointing at it.
+type LoggedCommit struct {
+	Commit git.Commit     // the commit
+	Class  Classification // how the commit is classified
+	Tags   []string       // version tags of the commit, lowest version first
+}
+
+// ShippedIssue is an issue referenced in commit messages and the version that shipped it.
+type ShippedIssue struct {
+	Issue string // the issue reference, e.g. #123 or PROJ-123
+	Tag   string // lowest version tag containing the most recent commit referencing the issue, or empty if unreleased
+}
+
+// ---------- History Functions ----------
+
+// ListReleases lists the version tags of the repository from the lowest to the highest version, using a
+// single git query. When a release and a build tag have the same version, the release comes first.
+// parameters:
+// - cfg: the repository configuration
+// - builds: whether to include hash-suffixed build tags
+// returns:
+// - []Release: the version tags
+// - error: an error object if something went wrong, otherwise nil
+func ListReleases(cfg *config.Config, builds bool) ([]Release, error) {
+	return releases(cfg, "", builds)
+}
+
+// FirstRelease finds the lowest version tag containing a commit, i.e. the first release that shipped it.
+// parameters:
+// - commit: the commit to look up
+// - cfg: the repository configuration
+// - prereleases: whether pre-releases count as releases
+// returns:
+// - Release: the first release containing the commit
+// - error: git.ErrNoTags if no release contains the commit, otherwise an error object if something went wrong
+func FirstRelease(commit string, cfg *config.Config, prereleases bool) (Release, error) {
+	hash, err := git.ResolveCommit(commit)
+	if err != nil {
+		return Release{}, err
+	}
+	containing, err := releases(cfg, hash, false)
+	if err != nil {
+		return Release{}, err
+	}
+	for _, release := range containing {
+		if release.Channel == ChannelStable || prereleases {
+			return release, nil
+		}
+	}
+	return Release{}, fmt.Errorf("no release contains commit %.7s yet: %w", hash, git.ErrNoTags)
+}
+
+// LogRange classifies the commits reachable from to but not from from and marks the version tags pointing
+// at them, using a single git query for the commits and one for the tags. Merges counted as units are
+// looked up separately.
+// parameters:
+// - from: the exclusive start of the range, e.g. v1.2.0, or empty for the whole history of to
+// - to: the inclusive end of the range, e.g. v1.5.0
+// - cfg: the repository configuration
+// returns:
+// - []LoggedCommit: the commits, oldest first
+// - error: an error object if something went wrong, otherwise nil
+func LogRange(from, to string, cfg *config.Config) ([]LoggedCommit, error) {
+	classifier, err := NewClassifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := git.GetCommitsBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	all, err := releases(cfg, "", true)
+	if err != nil {
+		return nil, err
+	}
+	tagsByCommit := map[string][]string{}
+	for _, release := range all {
+		tagsByCommit[release.Commit] = append(tagsByCommit[release.Commit], release.Tag)
+	}
+
+	logged := make([]LoggedCommit, 0, len(commits))
+	for _, commit := range commits {
+		class, err := ClassifyUnit(commit, classifier, cfg.History)
+		if err != nil {
+			return nil, err
+		}
+		logged = append(logged, LoggedCommit{Commit: commit, Class: class, Tags: tagsByCommit[commit.Hash]})
+	}
+	return logged, nil
+}
+
+// ListIssues lists the issues referenced by a revision range and the version that shipped each of them,
+// using a single git query for the commits and one for the history of the version tags.
+// parameters:
+// - rev: a revision range (e.g. v1.0.0..HEAD), a version tag standing for the commits it shipped, or any
+// other revision standing for its whole history
+// - cfg: the repository configuration
+// returns:
+// - []ShippedIssue: the issues, in order of first appearance from the oldest commit
+// - error: an error object if something went wrong, otherwise nil
+func ListIssues(rev string, cfg *config.Config) ([]ShippedIssue, error) {
+	extractor, err := issues.NewExtractor(cfg.Issues)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []git.Commit
+	if strings.Contains(rev, "..") {
+		commits, err = git.GetCommits(rev)
+	} else if _, _, parseErr := parseTag(scheme, rev, cfg.Tag.Prefix); parseErr == nil && git.TagExists(rev) {
+		var previous string
+		if previous, err = PreviousTag(rev, cfg); err != nil {
+			return nil, err
+		}
+		commits, err = git.GetCommitsBetween(previous, rev)
+	} else {
+		commits, err = git.GetCommitsBetween("", rev)
+	}
+	if err != nil {
+		return nil, err
+	}
+	shipped, err := shippedIn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var listed []ShippedIssue
+	index := map[string]int{}
+	for _, commit := range commits {
+		for _, issue := range extractor.Extract(commit) {
+			i, seen := index[issue]
+			if !seen {
+				i = len(listed)
+				index[issue] = i
+				listed = append(listed, ShippedIssue{Issue: issue})
+			}
+			// the most recent commit referencing an issue determines when it shipped
+			listed[i].Tag = shipped[commit.Hash]
+		}
+	}
+	return listed, nil
+}
+
+// ---------- Helper Functions ----------
+
+// shippedIn maps every commit contained in a version tag to the lowest version tag containing it, i.e. the
+// version that shipped it. When a release and a build tag have the same version, the release is preferred.
+func shippedIn(cfg *config.Config) (map[string]string, error) {
+	all, err := releases(cfg, "", true)
+	if err != nil || len(all) == 0 {
+		return map[string]string{}, err
+	}
+	graph, err := git.GetTagGraph(cfg.Tag.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// lower versions claim their history first, so later ones only reach the commits they added
+	shipped := make(map[string]string, len(graph))
+	for _, release := range all {
+		pending := []string{release.Commit}
+		for len(pending) > 0 {
+			next := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if _, claimed := shipped[next]; claimed {
+				continue
+			}
+			shipped[next] = release.Tag
+			pending = append(pending, graph[next]...)
+		}
+	}
+	return shipped, nil
+}
+
+// releases lists the version tags, optionally only those containing a commit, sorted by version.
+func releases(cfg *config.Config, contains string, builds bool) ([]Release, error) {
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := git.ListTags(contains)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Release
+	for _, tag := range tags {
+		v, isBuild, err := parseTag(scheme, tag.Name, cfg.Tag.Prefix)
+		if err != nil || (isBuild && !builds) {
+			continue
+		}
+		result = append(result, Release{Tag: tag.Name, Version: v, Commit: tag.Commit, Date: tag.Date, Channel: channel(v, isBuild)})
+	}
+
+	slices.SortStableFunc(result, func(a, b Release) int {
+		if c := scheme.Compare(a.Version, b.Version); c != 0 {
+			return c
+		}
+		// releases before build tags of the same version, then by name
+		if (a.Channel == ChannelBuild) != (b.Channel == ChannelBuild) {
+			if a.Channel == ChannelBuild {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return result, nil
+}
+
+// versionCommits returns the commits of all version tags, including build tags, each once.
+func versionCommits(cfg *config.Config) ([]string, error) {
+	all, err := releases(cfg, "", true)
+	if err != nil {
+		return nil, err
+	}
+	var commits []string
+	for _, release := range all {
+		if !slices.Contains(commits, release.Commit) {
+			commits = append(commits, release.Commit)
+		}
+	}
+	return commits, nil
+}
+
+// channel returns the release channel of a version.
+func channel(v Version, isBuild bool) string {
+	if isBuild {
+		return ChannelBuild
+	}
+	semver, ok := v.(SemVer)
+	if !ok || semver.Pre == "" {
+		return ChannelStable
+	}
+	id, _, _ := strings.Cut(semver.Pre, ".")
+	if strings.Trim(id, "0123456789") == "" {
+		return ChannelPrerelease
+	}
+	return id
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"errors"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/testutils"
+	"slices"
+	"testing"
+)
+
+// TestHistory validates that releases are listed by version with their channels, that the first release
+// containing a commit is found, and that the commits of a range are classified and marked with their tags.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestHistory(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"tag", "v1.0.0"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: search"},
+		{"tag", "v1.1.0-rc.1"},
+		{"tag", "v1.1.0-abc1234"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: search results"},
+		{"tag", "v1.1.0"},
+		{"commit", "-q", "--allow-empty", "-m", "docs: usage"},
+		{"tag", "v1.10.0"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: unreleased"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	cfg := config.Default()
+
+	releases, err := ListReleases(cfg, true)
+	if err != nil {
+		t.Fatalf("ListReleases failed: %v", err)
+	}
+	var listed []string
+	for _, release := range releases {
+		listed = append(listed, release.Tag+" "+release.Channel)
+	}
+	expected := []string{"v1.0.0 stable", "v1.1.0-rc.1 rc", "v1.1.0 stable", "v1.1.0-abc1234 build", "v1.10.0 stable"}
+	if !slices.Equal(listed, expected) {
+		t.Errorf("Expected releases %v, got %v", expected, listed)
+	}
+
+	feature := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD~3")
+	if first, err := FirstRelease(feature, cfg, false); err != nil || first.Tag != "v1.1.0" {
+		t.Errorf("Expected v1.1.0 to be the first release of the feature, got %q (%v)", first.Tag, err)
+	}
+	if first, err := FirstRelease(feature, cfg, true); err != nil || first.Tag != "v1.1.0-rc.1" {
+		t.Errorf("Expected v1.1.0-rc.1 to be the first pre-release of the feature, got %q (%v)", first.Tag, err)
+	}
+	if _, err := FirstRelease("HEAD", cfg, true); !errors.Is(err, git.ErrNoTags) {
+		t.Errorf("Expected ErrNoTags for an unreleased commit, got %v", err)
+	}
+
+	logged, err := LogRange("v1.0.0", "v1.10.0", cfg)
+	if err != nil {
+		t.Fatalf("LogRange failed: %v", err)
+	}
+	var levels []string
+	for _, c := range logged {
+		levels = append(levels, c.Commit.Subject+" "+c.Class.Level)
+	}
+	expected = []string{"feat: search minor", "fix: search results patch", "docs: usage none"}
+	if !slices.Equal(levels, expected) {
+		t.Errorf("Expected commits %v, got %v", expected, levels)
+	}
+	if !slices.Equal(logged[0].Tags, []string{"v1.1.0-rc.1", "v1.1.0-abc1234"}) {
+		t.Errorf("Expected the feature to be tagged v1.1.0-rc.1 and v1.1.0-abc1234, got %v", logged[0].Tags)
+	}
+
+	whole, err := LogRange("", "v1.0.0", cfg)
+	if err != nil || len(whole) != 1 || whole[0].Commit.Subject != "Initial commit" {
+		t.Errorf("Expected the history of v1.0.0 to be the initial commit, got %+v (%v)", whole, err)
+	}
+}
+
+// TestListIssues validates that issues are listed with the version that shipped them for a revision range,
+// a version tag and a revision that is not a tag.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestListIssues(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"commit", "-q", "--allow-empty", "-m", "feat: search (#1)"},
+		{"tag", "v1.0.0"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: search results (#2)"},
+		{"tag", "v1.0.1-abc1234"},
+		{"tag", "v1.0.1"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: search again (#1)"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: export (#3)"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	cfg := config.Default()
+
+	tests := []struct {
+		rev      string
+		expected []ShippedIssue
+	}{
+		{"HEAD", []ShippedIssue{{"#1", ""}, {"#2", "v1.0.1"}, {"#3", ""}}},
+		{"HEAD~1", []ShippedIssue{{"#1", ""}, {"#2", "v1.0.1"}}},
+		{"v1.0.1", []ShippedIssue{{"#2", "v1.0.1"}}},
+		{"v1.0.0..v1.0.1", []ShippedIssue{{"#2", "v1.0.1"}}},
+		{"v1.0.1..HEAD", []ShippedIssue{{"#1", ""}, {"#3", ""}}},
+	}
+	for _, tt := range tests {
+		listed, err := ListIssues(tt.rev, cfg)
+		if err != nil {
+			t.Fatalf("ListIssues(%s) failed: %v", tt.rev, err)
+		}
+		if !slices.Equal(listed, tt.expected) {
+			t.Errorf("ListIssues(%s): expected %v, got %v", tt.rev, tt.expected, listed)
+		}
+	}
+
+	if listed, err := ListIssues("v1.0.0", cfg); err != nil || !slices.Equal(listed, []ShippedIssue{{"#1", "v1.0.0"}}) {
+		t.Errorf("Expected v1.0.0 to ship #1, got %v (%v)", listed, err)
+	}
+}
+
+// TestPlanIgnoresNonVersionTags validates that tags sharing the tag prefix without being versions, e.g.
+// v-next, do not mark the commits they contain as tagged.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestPlanIgnoresNonVersionTags(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	if err := testutils.RunGitCommand("tag", "v1.0.0"); err != nil {
+		t.Fatalf("Failed to create tag 'v1.0.0': %v", err)
+	}
+	testutils.CreateAndCommitFile(t, "a.txt", "feat: add a")
+	for _, tag := range []string{"v-next", "vendor-sync", "deploy-prod"} {
+		if err := testutils.RunGitCommand("tag", tag); err != nil {
+			t.Fatalf("Failed to create tag %q: %v", tag, err)
+		}
+	}
+	testutils.CreateAndCommitFile(t, "b.txt", "fix: fix b")
+
+	plan, err := PlanUntaggedCommits("HEAD", config.Default())
+	if err != nil {
+		t.Fatalf("PlanUntaggedCommits failed: %v", err)
+	}
+	if plan.Base != "v1.0.0" || len(plan.commits) != 2 {
+		t.Errorf("Expected both commits after v1.0.0 to be untagged, got base %q and %v", plan.Base, plan.commits)
+	}
+}
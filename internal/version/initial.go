@@ -0,0 +1,91 @@
+package version
+
+import (
+	"fmt"
+)
+
+const (
+	ZeroPolicyBreakingMinor = "breaking-minor" // while at 0.x, breaking changes bump minor
+	ZeroPolicyFeatPatch     = "feat-patch"     // while at 0.x, breaking changes bump minor and features bump patch
+	ZeroPolicyStrict        = "strict"         // plain semantic versioning: a breaking change releases 1.0.0
+)
+
+// ---------- Initial Development Functions ----------
+
+// InitialDevelopmentLevel adjusts a level of version increment for versions in initial
+// development (major version 0), so that breaking changes do not accidentally declare a
+// stable 1.0.0 release. Versions from 1.0.0 on are not affected.
+// parameters:
+// - current: the version being incremented
+// - level: the level of version increment detected for a change
+// - policy: the configured policy (breaking-minor, feat-patch or strict; empty for breaking-minor)
+// returns:
+// - string: the level to apply
+// - error: an error object if the policy is unknown, otherwise nil
+func InitialDevelopmentLevel(current SemVer, level, policy string) (string, error) {
+	policy, err := zeroPolicy(policy)
+	if err != nil {
+		return "", err
+	}
+
+	if current.Major != 0 || policy == ZeroPolicyStrict {
+		return level, nil
+	}
+
+	switch {
+	case level == "major":
+		return "minor", nil
+	case level == "minor" && policy == ZeroPolicyFeatPatch:
+		return "patch", nil
+	}
+	return level, nil
+}
+
+// PromoteVersion validates the version that ends initial development, 1.0.0 unless another
+// major release is given.
+// parameters:
+// - arg: the requested version, or empty for 1.0.0
+// - prefix: the prefix of version tags
+// returns:
+// - SemVer: the version to release
+// - error: an error object if the version is not a valid stable release or the project is already stable
+func PromoteVersion(arg, prefix string) (SemVer, error) {
+	target := SemVer{Major: 1}
+	if arg != "" {
+		v, err := ParseVersionArg(arg, prefix)
+		if err != nil {
+			return SemVer{}, err
+		}
+		target = v
+	}
+	if target.Major < 1 || target.Minor != 0 || target.Patch != 0 || target.Pre != "" {
+		return SemVer{}, fmt.Errorf("cannot promote to %s: expected a stable major release such as 1.0.0", target)
+	}
+
+	latest, _, err := LatestVersion(prefix)
+	if err != nil {
+		return SemVer{}, err
+	}
+	if latest.Major != 0 {
+		return SemVer{}, fmt.Errorf("already past initial development: latest version is %s", latest.Tag(prefix))
+	}
+	return target, nil
+}
+
+// ---------- Helper Functions ----------
+
+// zeroPolicy validates a configured zero_policy, so that planning and explicit bumps agree on it.
+// parameters:
+// - policy: the configured policy
+// returns:
+// - string: the policy, breaking-minor if none is configured
+// - error: an error object if the policy is unknown, otherwise nil
+func zeroPolicy(policy string) (string, error) {
+	switch policy {
+	case ZeroPolicyBreakingMinor, ZeroPolicyFeatPatch, ZeroPolicyStrict:
+		return policy, nil
+	case "":
+		return ZeroPolicyBreakingMinor, nil
+	}
+	return "", fmt.Errorf("unknown zero_policy %q: expected %s, %s or %s", policy, ZeroPolicyBreakingMinor, ZeroPolicyFeatPatch, ZeroPolicyStrict)
+}
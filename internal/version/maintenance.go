@@ -0,0 +1,146 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/policy"
+	"regexp"
+	"strconv"
+)
+
+// Modes of handling commits on maintenance branches that warrant a bump leaving the version line.
+const (
+	MaintenanceCap    = "cap"    // apply the highest level the line allows instead
+	MaintenanceReject = "reject" // refuse to tag
+)
+
+// ErrOutsideLine is returned when a version or bump would leave the version line of a maintenance branch.
+var ErrOutsideLine = errors.New("version is outside the maintenance line")
+
+// linePattern matches the version line at the end of a maintenance branch name, e.g. release/1.x or 2.3.x.
+var linePattern = regexp.MustCompile(`(?:^|[/-])v?(\d+)\.(?:(\d+)\.)?x$`)
+
+// Line is the version line of a maintenance branch: all versions of a major (1.x) or minor (2.3.x) version.
+type Line struct {
+	Branch string // the maintenance branch
+	Major  int    // major version of the line
+	Minor  int    // minor version of the line, or -1 for a major line
+}
+
+// ---------- Maintenance Functions ----------
+
+// MaintenanceLine returns the version line of a maintenance branch.
+// parameters:
+// - branch: the branch to tag, or empty or HEAD for the current branch
+// - cfg: the repository configuration
+// returns:
+// - *Line: the version line, or nil if the branch is not a maintenance branch
+// - error: an error object if a maintenance branch names no version line or something went wrong, otherwise nil
+func MaintenanceLine(branch string, cfg *config.Config) (*Line, error) {
+	if len(cfg.Branches.Maintenance) == 0 {
+		return nil, nil
+	}
+	if branch == "" || branch == "HEAD" {
+		current, err := git.GetCurrentBranch()
+		if errors.Is(err, git.ErrDetachedHead) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		branch = current
+	}
+	if !policy.MatchBranch(cfg.Branches.Maintenance, branch) {
+		return nil, nil
+	}
+
+	m := linePattern.FindStringSubmatch(branch)
+	if m == nil {
+		return nil, fmt.Errorf("maintenance branch %s does not end in a version line such as 1.x or 2.3.x", branch)
+	}
+	if err := requireSemVer(cfg); err != nil {
+		return nil, fmt.Errorf("maintenance branch %s: %w", branch, err)
+	}
+
+	line := &Line{Branch: branch, Minor: -1}
+	line.Major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		line.Minor, _ = strconv.Atoi(m[2])
+	}
+	return line, nil
+}
+
+// String returns the line in the form of its branch name, e.g. 1.x or 2.3.x.
+func (l *Line) String() string {
+	if l.Minor < 0 {
+		return fmt.Sprintf("%d.x", l.Major)
+	}
+	return fmt.Sprintf("%d.%d.x", l.Major, l.Minor)
+}
+
+// Contains reports whether a version belongs to the line.
+// parameters:
+// - v: the version
+// returns:
+// - bool: true if the version belongs to the line
+func (l *Line) Contains(v SemVer) bool {
+	return v.Major == l.Major && (l.Minor < 0 || v.Minor == l.Minor)
+}
+
+// MaxLevel returns the highest level of version increment that stays within the line.
+// returns:
+// - string: minor for major lines, patch for minor lines
+func (l *Line) MaxLevel() string {
+	if l.Minor < 0 {
+		return "minor"
+	}
+	return "patch"
+}
+
+// Constrain checks a level of version increment against the line, capping or rejecting levels that leave it.
+// parameters:
+// - level: the level of version increment
+// - mode: MaintenanceCap or MaintenanceReject
+// returns:
+// - string: the level to apply
+// - error: an error wrapping ErrOutsideLine if the level is rejected, otherwise nil
+func (l *Line) Constrain(level, mode string) (string, error) {
+	if levelRank(level) <= levelRank(l.MaxLevel()) {
+		return level, nil
+	}
+	switch mode {
+	case MaintenanceCap:
+		return l.MaxLevel(), nil
+	case MaintenanceReject:
+		return "", fmt.Errorf("%w: a %s bump would leave %s, which only allows %s bumps on %s", ErrOutsideLine, level, l, l.MaxLevel(), l.Branch)
+	}
+	return "", fmt.Errorf("invalid maintenance_bumps %q: expected %s or %s", mode, MaintenanceCap, MaintenanceReject)
+}
+
+// Latest finds the highest version of the line among the tags carrying the configured prefix.
+// Hash-suffixed build tags count as their core version.
+// parameters:
+// - prefix: the prefix of version tags
+// returns:
+// - string: the name of the tag
+// - SemVer: the highest version of the line
+// - error: git.ErrNoTags if the line has no version tags, otherwise an error object if something went wrong
+func (l *Line) Latest(prefix string) (string, SemVer, error) {
+	tags, err := git.RunGitCommand("tag", "--list", prefix+"*")
+	if err != nil {
+		return "", SemVer{}, fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+
+	var inLine []string
+	for _, tag := range tags {
+		if v, _, err := parseTag(SemVerScheme{}, tag, prefix); err == nil && l.Contains(v.(SemVer)) {
+			inLine = append(inLine, tag)
+		}
+	}
+	tag, latest, found := highestVersion(SemVerScheme{}, inLine, prefix)
+	if !found {
+		return "", SemVer{}, fmt.Errorf("failed to find the latest version of %s on %s (tag its first version with set): %w", l, l.Branch, git.ErrNoTags)
+	}
+	return tag, latest.(SemVer), nil
+}
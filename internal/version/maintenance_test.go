@@ -0,0 +1,76 @@
+package version
+
+import (
+	"errors"
+	"git-tagger/internal/config"
+	"git-tagger/internal/testutils"
+	"strings"
+	"testing"
+)
+
+// TestMaintenanceLine validates that maintenance branches plan versions from the latest tag of their line
+// and cap or reject bumps that would leave it.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestMaintenanceLine(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"tag", "v2.3.0"},
+		{"checkout", "-q", "-b", "release/2.3.x"},
+		{"checkout", "-q", "-"},
+		{"commit", "-q", "--allow-empty", "-m", "feat!: new api"},
+		{"tag", "v3.0.0"},
+		{"checkout", "-q", "release/2.3.x"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: backported feature"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Branches.Maintenance = []string{"release/*"}
+
+	plan, err := PlanUntaggedCommits("release/2.3.x", cfg)
+	if err != nil {
+		t.Fatalf("PlanUntaggedCommits failed: %v", err)
+	}
+	if plan.Base != "v2.3.0" {
+		t.Errorf("Expected the plan to start from v2.3.0, got %q", plan.Base)
+	}
+	if len(plan.Tags) != 1 || plan.Tags[0].Version.String() != "2.3.1" || plan.Tags[0].Level != "patch" {
+		t.Fatalf("Expected the feature to be capped to 2.3.1, got %+v", plan.Tags)
+	}
+	if !strings.Contains(plan.Tags[0].Reason, "capped to patch on 2.3.x") {
+		t.Errorf("Expected the reason to explain the cap, got %q", plan.Tags[0].Reason)
+	}
+
+	cfg.Branches.MaintenanceBumps = MaintenanceReject
+	if _, err := PlanUntaggedCommits("release/2.3.x", cfg); !errors.Is(err, ErrOutsideLine) {
+		t.Errorf("Expected the feature to be rejected, got %v", err)
+	}
+	commit := plan.Tags[0].Commit
+	if _, err := PlanWithOverrides("release/2.3.x", cfg, map[string]Override{commit: {Level: "minor"}}); !errors.Is(err, ErrOutsideLine) {
+		t.Errorf("Expected a chosen minor bump to be rejected, got %v", err)
+	}
+	if skipped, err := PlanWithOverrides("release/2.3.x", cfg, map[string]Override{commit: {Level: "none"}}); err != nil || len(skipped.Tags) != 0 {
+		t.Errorf("Expected the commit to be skipped, got %+v (%v)", skipped, err)
+	}
+	if _, err := BumpVersion("minor", cfg); !errors.Is(err, ErrOutsideLine) {
+		t.Errorf("Expected an explicit minor bump to be rejected, got %v", err)
+	}
+	next, err := BumpVersion("patch", cfg)
+	if err != nil || next.String() != "2.3.1" {
+		t.Errorf("Expected a patch bump to 2.3.1, got %v (%v)", next, err)
+	}
+	if _, err := TagRelease(SemVer{Major: 2, Minor: 4}, "HEAD", cfg, false); !errors.Is(err, ErrOutsideLine) {
+		t.Errorf("Expected 2.4.0 to be refused on release/2.3.x, got %v", err)
+	}
+	if tag, err := TagRelease(next, "HEAD", cfg, false); err != nil || tag != "v2.3.1" {
+		t.Errorf("Expected v2.3.1 to be tagged below the latest version v3.0.0, got %q (%v)", tag, err)
+	}
+}
@@ -0,0 +1,523 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/issues"
+	"git-tagger/internal/journal"
+	"git-tagger/internal/logging"
+	"git-tagger/internal/policy"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoRelease is returned when there are no commits that warrant a new version.
+	ErrNoRelease = errors.New("no release needed")
+	// ErrPlanChanged is returned when a previewed plan no longer matches the repository, e.g. because
+	// another run tagged some of its commits.
+	ErrPlanChanged = errors.New("the plan changed since it was computed")
+)
+
+// PlannedTag is a version tag that a tagging run will create for a commit.
+type PlannedTag struct {
+	Commit  string   // full hash of the commit to tag
+	Message string   // commit message used to classify the commit
+	Level   string   // detected level of version increment
+	Reason  string   // explanation of how the level was detected
+	Version Version  // version assigned to the commit, without the hash suffix
+	Tag     string   // name of the tag to create, including the hash suffix
+	Issues  []string // issues referenced by the commit message
+}
+
+// Override replaces what planning detects for a commit.
+type Override struct {
+	Level   string  // level of version increment to apply instead of the detected one; none leaves the commit untagged
+	Version Version // version to assign instead of incrementing, if set; later commits increment from it
+}
+
+// Plan describes the tags a tagging run will create, oldest commit first.
+type Plan struct {
+	Branch      string       // branch or revision being tagged
+	Base        string       // tag the plan starts from, empty if the repository has no version tags
+	BaseVersion Version      // version the plan starts from
+	Tags        []PlannedTag // tags to create
+	Skipped     []PlannedTag // untagged commits that warrant no release, without a version or tag
+
+	commits []string             // untagged commits in the order they are versioned, oldest first
+	dates   map[string]time.Time // dates of the tagged commits by hash, used as tag dates by ApplyBootstrap
+}
+
+// ---------- Plan Functions ----------
+
+// PlanUntaggedCommits computes the tags that tagging the untagged commits of a branch would create,
+// without creating them.
+// parameters:
+// - branch: the branch from which to find untagged commits
+// - cfg: the repository configuration
+// returns:
+// - *Plan: the computed plan
+// - error: an error object if something went wrong, otherwise nil
+func PlanUntaggedCommits(branch string, cfg *config.Config) (*Plan, error) {
+	return PlanWithOverrides(branch, cfg, nil)
+}
+
+// PlanWithOverrides computes the tags like PlanUntaggedCommits, applying the levels of version increment
+// or versions chosen for individual commits instead of the detected ones. Chosen levels are not adjusted
+// for initial development and are rejected if they leave the version line of a maintenance branch.
+// parameters:
+// - branch: the branch from which to find untagged commits
+// - cfg: the repository configuration
+// - overrides: the choices by full commit hash
+// returns:
+// - *Plan: the computed plan
+// - error: an error object if something went wrong, otherwise nil
+func PlanWithOverrides(branch string, cfg *config.Config, overrides map[string]Override) (*Plan, error) {
+	for commit, override := range overrides {
+		if override.Level != "" && !isLevel(override.Level) {
+			return nil, fmt.Errorf("invalid level %q for commit %s: expected major, minor, patch or none", override.Level, commit)
+		}
+	}
+
+	// Find all untagged commits
+	history := cfg.History
+	tagged, err := versionCommits(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list version tags: %w", err)
+	}
+	untaggedCommits, err := git.FindUntagged(branch, tagged, history.FirstParent || history.MergeAsUnit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untagged commits: %w", err)
+	}
+
+	plan := &Plan{Branch: branch}
+	for _, details := range untaggedCommits {
+		plan.commits = append(plan.commits, details.Hash)
+	}
+	if len(untaggedCommits) == 0 {
+		return plan, nil
+	}
+
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the latest version (if any); hash suffixes count as their core version.
+	// Maintenance branches start from the latest version of their line.
+	line, err := MaintenanceLine(branch, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var latest Version
+	if line != nil {
+		_, latestInLine, err := line.Latest(cfg.Tag.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		latest = latestInLine
+		plan.Base = latest.Tag(cfg.Tag.Prefix)
+	} else {
+		_, latestTag, found, err := LatestSchemeVersion(scheme, cfg.Tag.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		latest = latestTag
+		if found {
+			plan.Base = latest.Tag(cfg.Tag.Prefix)
+		}
+	}
+	plan.BaseVersion = latest
+
+	classifier, err := NewClassifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	extractor, err := issues.NewExtractor(cfg.Issues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track the current version from the oldest to the most recent commit
+	current := latest
+	for _, details := range untaggedCommits {
+		commit := details.Hash
+
+		// Determine the increment level based on the commit message
+		class, err := ClassifyUnit(details, classifier, history)
+		if err != nil {
+			return nil, err
+		}
+		if class.Rule == "" && class.Via == "" {
+			logging.Logger().Debug("Unrecognized commit message, using the default level", "subject", details.Subject, "level", class.Level)
+		}
+		level, reason := class.Level, class.String()
+		override, overridden := overrides[commit]
+		if override.Level != "" {
+			level, reason = override.Level, fmt.Sprintf("set to %s, detected %s", override.Level, reason)
+		}
+		if level == "none" && override.Version == nil {
+			plan.Skipped = append(plan.Skipped, PlannedTag{Commit: commit, Message: details.Subject, Level: level, Reason: reason, Issues: extractor.Extract(details)})
+			continue
+		}
+
+		if override.Version != nil {
+			current, err = overrideVersion(scheme, current, override.Version, line)
+			if err != nil {
+				return nil, fmt.Errorf("cannot tag commit %s (%q): %w", commit, details.Subject, err)
+			}
+			reason = fmt.Sprintf("version set to %s, detected %s", current, reason)
+		} else {
+			// Keep 0.x versions in initial development unless the policy is strict
+			if semver, ok := current.(SemVer); ok && !overridden {
+				level, err = InitialDevelopmentLevel(semver, class.Level, cfg.Versioning.ZeroPolicy)
+				if err != nil {
+					return nil, err
+				}
+				if level != class.Level {
+					reason += fmt.Sprintf(", %s as %s before 1.0.0", class.Level, level)
+				}
+			}
+			if line != nil {
+				mode := cfg.Branches.MaintenanceBumps
+				if overridden {
+					mode = MaintenanceReject
+				}
+				constrained, err := line.Constrain(level, mode)
+				if err != nil {
+					return nil, fmt.Errorf("cannot tag commit %s (%q): %w", commit, details.Subject, err)
+				}
+				if constrained != level {
+					reason += fmt.Sprintf(", %s capped to %s on %s", level, constrained, line)
+					level = constrained
+				}
+			}
+
+			current, err = scheme.Next(current, level)
+			if err != nil {
+				return nil, fmt.Errorf("failed to increment version for commit %s: %w", commit, err)
+			}
+		}
+
+		plan.Tags = append(plan.Tags, PlannedTag{
+			Commit:  commit,
+			Message: details.Subject,
+			Level:   level,
+			Reason:  reason,
+			Version: current,
+			Tag:     fmt.Sprintf("%s-%s", current.Tag(cfg.Tag.Prefix), details.ShortHash),
+			Issues:  extractor.Extract(details),
+		})
+	}
+
+	return plan, nil
+}
+
+// Commits returns the planned and skipped commits together, in the order they are versioned.
+// returns:
+// - []PlannedTag: the untagged commits, oldest first
+func (p *Plan) Commits() []PlannedTag {
+	byCommit := make(map[string]PlannedTag, len(p.Tags)+len(p.Skipped))
+	for _, planned := range slices.Concat(p.Tags, p.Skipped) {
+		byCommit[planned.Commit] = planned
+	}
+	commits := make([]PlannedTag, 0, len(byCommit))
+	for _, commit := range p.commits {
+		if planned, ok := byCommit[commit]; ok {
+			commits = append(commits, planned)
+		}
+	}
+	return commits
+}
+
+// Next returns the version the plan assigns to its most recent commit.
+// returns:
+// - Version: the next version
+// - error: ErrNoRelease if the plan creates no tags, otherwise nil
+func (p *Plan) Next() (Version, error) {
+	if len(p.Tags) == 0 {
+		return nil, ErrNoRelease
+	}
+	return p.Tags[len(p.Tags)-1].Version, nil
+}
+
+// ApplyPlan creates the tags of a plan, recording them in a run journal.
+// If creating a tag fails, the tags already created by the run are rolled back.
+// parameters:
+// - plan: the plan to apply
+// - cfg: the repository configuration
+// returns:
+// - string: the ID of the run journal, for undo
+// - error: an error object if something went wrong, otherwise nil
+func ApplyPlan(plan *Plan, cfg *config.Config) (string, error) {
+	// Record every created tag and commit so a failed or unwanted run can be reverted
+	run, err := journal.Start(plan.Branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to start run journal: %w", err)
+	}
+
+	// Move the latest version onto a release commit, if configured
+	if cfg.Release.OnVersionTag && len(plan.Tags) > 0 {
+		if err := planReleaseCommit(plan, cfg, run); err != nil {
+			return "", rollbackRun(run, err)
+		}
+	}
+
+	opts := git.TagOptions{Sign: cfg.Tag.Sign}
+	for _, planned := range plan.Tags {
+		logging.Logger().Info("Tagging commit", "commit", planned.Commit, "tag", planned.Tag)
+		logging.Logger().Debug("Classified commit", "commit", planned.Commit, "level", planned.Level, "reason", planned.Reason)
+
+		// Create a tag for the untagged commit
+		message, err := formatVersion(planned.Version, cfg.Tag.Prefix, cfg.Tag.Message, map[string]any{"Commit": planned.Commit, "Tag": planned.Tag})
+		if err != nil {
+			return "", rollbackRun(run, fmt.Errorf("failed to format tag message: %w", err))
+		}
+		if err := run.Record(planned.Tag, planned.Commit); err != nil {
+			return "", rollbackRun(run, err)
+		}
+		err = git.CreateTagWithOptions(planned.Tag, annotation(message, planned.Issues), planned.Commit, opts)
+		if err != nil {
+			return "", rollbackRun(run, fmt.Errorf("failed to create tag %s for commit %s: %w", planned.Tag, planned.Commit, err))
+		}
+		if err := run.Confirm(planned.Tag); err != nil {
+			return "", rollbackRun(run, err)
+		}
+	}
+
+	if err := pushRun(run, cfg); err != nil {
+		return "", err
+	}
+	if err := run.Finish(journal.StatusCompleted); err != nil {
+		return "", err
+	}
+
+	logging.Logger().Info("Successfully tagged all untagged commits.", "run", run.RunID)
+	return run.RunID, nil
+}
+
+// ApplyUnchanged recomputes a previewed plan and applies it only if it still creates the same tags for the
+// same commits. The caller holds the repository lock, so that no other run changes the plan in between.
+// parameters:
+// - previewed: the plan computed before the lock was taken
+// - overrides: the choices the previewed plan was computed with, or nil
+// - cfg: the repository configuration
+// returns:
+// - *Plan: the applied plan, whose last tag may have moved onto a release commit
+// - string: the ID of the run journal, for undo
+// - error: ErrPlanChanged if the plan changed, otherwise an error object if something went wrong
+func ApplyUnchanged(previewed *Plan, overrides map[string]Override, cfg *config.Config) (*Plan, string, error) {
+	plan, err := PlanWithOverrides(previewed.Branch, cfg, overrides)
+	if err != nil {
+		return nil, "", err
+	}
+	same := slices.EqualFunc(plan.Tags, previewed.Tags, func(a, b PlannedTag) bool {
+		return a.Commit == b.Commit && a.Tag == b.Tag
+	})
+	if !same {
+		return nil, "", ErrPlanChanged
+	}
+	runID, err := ApplyPlan(plan, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return plan, runID, nil
+}
+
+// ClassifyUnit classifies a commit, using its pull request title or treating it as a merge
+// of several commits when the history configuration asks for it.
+// parameters:
+// - commit: the commit to classify
+// - classifier: the classifier to apply
+// - history: the history configuration
+// returns:
+// - Classification: the level of the commit and how it was detected
+// - error: an error object if something went wrong, otherwise nil
+func ClassifyUnit(commit git.Commit, classifier *Classifier, history config.HistoryConfig) (Classification, error) {
+	class := classifier.Classify(commit)
+	if history.SquashTitles {
+		if title, ok := pullRequestTitle(commit); ok {
+			class = classifier.Classify(git.Commit{Hash: commit.Hash, Subject: title, Trailers: commit.Trailers})
+			class.Via = "pull request title"
+
+			// a recognized title wins; otherwise a merge may still be judged by its merged commits
+			if class.Rule != "" {
+				return class, nil
+			}
+		}
+	}
+
+	if !history.MergeAsUnit {
+		return class, nil
+	}
+
+	parents := commit.Parents
+	if parents == nil {
+		var err error
+		if parents, err = git.GetParents(commit.Hash); err != nil {
+			return Classification{}, err
+		}
+	}
+	if len(parents) < 2 {
+		return class, nil
+	}
+
+	merged, err := git.GetCommits(commit.Hash + "^1.." + commit.Hash)
+	if err != nil {
+		return Classification{}, fmt.Errorf("failed to list commits merged by %s: %w", commit.Hash, err)
+	}
+	merged = slices.DeleteFunc(merged, func(c git.Commit) bool { return c.Hash == commit.Hash })
+	if len(merged) == 0 {
+		return class, nil
+	}
+
+	// the merge counts as a single change with the highest bump among its merged commits
+	var unit Classification
+	nested := history
+	nested.MergeAsUnit = false
+	for i, details := range merged {
+		mergedClass, err := ClassifyUnit(details, classifier, nested)
+		if err != nil {
+			return Classification{}, err
+		}
+		if i == 0 || levelRank(mergedClass.Level) > levelRank(unit.Level) {
+			unit = mergedClass
+		}
+	}
+	unit.Via = fmt.Sprintf("highest of %d merged commit(s)", len(merged))
+	return unit, nil
+}
+
+// planReleaseCommit creates the release commit for the latest version of a plan, records it in the run
+// journal and retargets its tag to that commit. Plans whose branch or latest commit is not checked out are left unchanged.
+// parameters:
+// - plan: the plan to update
+// - cfg: the repository configuration
+// - run: the journal of the run applying the plan
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func planReleaseCommit(plan *Plan, cfg *config.Config, run *journal.Journal) error {
+	last := &plan.Tags[len(plan.Tags)-1]
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	current, err := git.GetCurrentBranch()
+	if err != nil && !errors.Is(err, git.ErrDetachedHead) {
+		return err
+	}
+	if last.Commit != head || (plan.Branch != "" && plan.Branch != current) {
+		logging.Logger().Info("Skipping release commit: the branch is not checked out.", "branch", plan.Branch)
+		return nil
+	}
+	// The release commit is created on the branch being tagged, which is judged like in TagRelease
+	if _, err := policy.AllowCommit(last.Commit, plan.Branch, cfg.Branches, policy.KindRelease); errors.Is(err, policy.ErrNotAllowed) {
+		logging.Logger().Info("Skipping release commit", "error", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	commit, err := PrepareRelease(last.Version, last.Commit, cfg)
+	if err != nil || commit == last.Commit {
+		return err
+	}
+	if err := run.RecordCommit(commit, last.Commit); err != nil {
+		return err
+	}
+
+	shortHash, err := git.GetShortCommitHash(commit)
+	if err != nil {
+		return fmt.Errorf("failed to get short hash for commit %s: %w", commit, err)
+	}
+	last.Commit = commit
+	last.Tag = fmt.Sprintf("%s-%s", last.Version.Tag(cfg.Tag.Prefix), shortHash)
+	return nil
+}
+
+// overrideVersion checks a version chosen for a commit, which must be higher than the version before it
+// and, on maintenance branches, within the version line.
+// parameters:
+// - scheme: the versioning scheme
+// - current: the version before the commit
+// - chosen: the chosen version
+// - line: the version line of a maintenance branch, or nil
+// returns:
+// - Version: the chosen version
+// - error: an error object if the version is not allowed, otherwise nil
+func overrideVersion(scheme Scheme, current, chosen Version, line *Line) (Version, error) {
+	if scheme.Compare(chosen, current) <= 0 {
+		return nil, fmt.Errorf("chosen version %s is not higher than %s", chosen, current)
+	}
+	if semver, ok := chosen.(SemVer); ok && line != nil && !line.Contains(semver) {
+		return nil, fmt.Errorf("%w: %s does not belong to %s on %s", ErrOutsideLine, chosen, line, line.Branch)
+	}
+	return chosen, nil
+}
+
+// annotation appends the referenced issues to a tag message.
+func annotation(message string, referenced []string) string {
+	if len(referenced) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s\n\nIssues: %s", message, strings.Join(referenced, ", "))
+}
+
+// pushRun pushes the tags of a run to git.remote_name if git.push_tags is set, replacing the tags the run
+// replaced there. New tags are pushed atomically, so if pushing fails the run is rolled back locally and
+// only the tags pushed before the failure are deleted from the remote.
+// parameters:
+// - run: the journal of the run, with all its tags recorded
+// - cfg: the repository configuration
+// returns:
+// - error: an error object if pushing failed, otherwise nil
+func pushRun(run *journal.Journal, cfg *config.Config) error {
+	if !cfg.Git.PushTags || len(run.Tags) == 0 {
+		return nil
+	}
+	var created, replaced []string
+	for _, entry := range run.Tags {
+		if entry.Replaced != "" {
+			replaced = append(replaced, entry.Tag)
+		} else {
+			created = append(created, entry.Tag)
+		}
+	}
+
+	remote := cfg.Git.RemoteName
+	logging.Logger().Info("Pushing tags", "remote", remote, "tags", len(run.Tags))
+	if err := git.PushTags(remote, false, created...); err != nil {
+		return rollbackRun(run, fmt.Errorf("failed to push tags to %s: %w", remote, err))
+	}
+	if err := git.PushTags(remote, true, replaced...); err != nil {
+		cause := fmt.Errorf("failed to push replaced tags to %s: %w", remote, err)
+		if err := git.DeleteRemoteTags(remote, created...); err != nil {
+			cause = errors.Join(cause, err)
+		}
+		return rollbackRun(run, cause)
+	}
+	return nil
+}
+
+// rollbackRun deletes the tags created so far by a failed run and marks it as rolled back.
+// parameters:
+// - run: the journal of the failed run
+// - cause: the error that made the run fail
+// returns:
+// - error: the original error, joined with any error raised while rolling back
+func rollbackRun(run *journal.Journal, cause error) error {
+	if len(run.Tags) > 0 {
+		logging.Logger().Warn("Rolling back the tags created by the run", "tags", len(run.Tags), "run", run.RunID)
+	}
+
+	if err := run.Rollback(""); err != nil {
+		return errors.Join(cause, fmt.Errorf("rollback of run %s failed: %w", run.RunID, err))
+	}
+	if err := run.Finish(journal.StatusRolledBack); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
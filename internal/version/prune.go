@@ -0,0 +1,164 @@
+package version
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PrunePolicy describes which hash-suffixed build tags may be deleted.
+// A build tag is a prune candidate if any enabled rule selects it; clean release tags are never selected.
+type PrunePolicy struct {
+	KeepPerMinor int           // keep only the newest N build tags of each major.minor line (0 disables); see versionLine
+	Superseded   bool          // prune build tags whose version is not newer than the latest release tag
+	MaxAge       time.Duration // prune build tags created longer ago than this (0 disables)
+}
+
+// PruneCandidate is a build tag selected for deletion together with the reason it was selected.
+type PruneCandidate struct {
+	Tag    string
+	Date   time.Time
+	Reason string
+}
+
+// ---------- Prune Functions ----------
+
+// FindPruneCandidates applies a retention policy to a set of tags. Tags are parsed with the versioning
+// scheme and tag prefix of the configuration; tags that are no versions of it are ignored.
+// parameters:
+// - tags: all tags of the repository with their creation dates
+// - policy: the retention policy to apply
+// - cfg: the repository configuration
+// - now: the reference time for age-based rules
+// returns:
+// - []PruneCandidate: the build tags to delete, oldest version first
+// - error: an error object if the versioning scheme is invalid, otherwise nil
+func FindPruneCandidates(tags []git.TagInfo, policy PrunePolicy, cfg *config.Config, now time.Time) ([]PruneCandidate, error) {
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	type build struct {
+		git.TagInfo
+		version Version
+	}
+	var builds []build
+	var latestRelease Version
+	for _, tag := range tags {
+		v, isBuild, err := parseTag(scheme, tag.Name, cfg.Tag.Prefix)
+		switch {
+		case err != nil:
+		case isBuild:
+			builds = append(builds, build{tag, v})
+		case channel(v, false) == ChannelStable:
+			if latestRelease == nil || scheme.Compare(v, latestRelease) > 0 {
+				latestRelease = v
+			}
+		}
+	}
+
+	// order build tags by version, then by creation date for tags sharing a version
+	slices.SortStableFunc(builds, func(a, b build) int {
+		if c := scheme.Compare(a.version, b.version); c != 0 {
+			return c
+		}
+		return a.Date.Compare(b.Date)
+	})
+
+	reasons := make(map[string][]string)
+
+	if policy.Superseded && latestRelease != nil {
+		for _, tag := range builds {
+			if scheme.Compare(tag.version, latestRelease) <= 0 {
+				reasons[tag.Name] = append(reasons[tag.Name], fmt.Sprintf("superseded by release %s", latestRelease.Tag(cfg.Tag.Prefix)))
+			}
+		}
+	}
+
+	if policy.MaxAge > 0 {
+		cutoff := now.Add(-policy.MaxAge)
+		for _, tag := range builds {
+			if tag.Date.Before(cutoff) {
+				reasons[tag.Name] = append(reasons[tag.Name], fmt.Sprintf("older than %s", policy.MaxAge))
+			}
+		}
+	}
+
+	if policy.KeepPerMinor > 0 {
+		lines := make(map[string][]build)
+		for _, tag := range builds {
+			line := cfg.Tag.Prefix + versionLine(tag.version)
+			lines[line] = append(lines[line], tag)
+		}
+		for line, lineTags := range lines {
+			// builds are sorted oldest first, so everything before the last N is pruned
+			for _, tag := range lineTags[:max(0, len(lineTags)-policy.KeepPerMinor)] {
+				reasons[tag.Name] = append(reasons[tag.Name], fmt.Sprintf("more than %d build tags on %s.x", policy.KeepPerMinor, line))
+			}
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, tag := range builds {
+		if r, ok := reasons[tag.Name]; ok {
+			candidates = append(candidates, PruneCandidate{Tag: tag.Name, Date: tag.Date, Reason: strings.Join(r, "; ")})
+		}
+	}
+	return candidates, nil
+}
+
+// PruneTags deletes the given candidates locally and, if a remote is given, from the remote as well,
+// while holding the repository lock.
+// parameters:
+// - candidates: the build tags to delete
+// - remote: the remote to delete the tags from, or empty to only delete local tags
+// - cfg: the repository configuration
+// returns:
+// - error: an error object if something went wrong, otherwise nil
+func PruneTags(candidates []PruneCandidate, remote string, cfg *config.Config) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		if _, isBuild, err := parseTag(scheme, candidate.Tag, cfg.Tag.Prefix); err != nil || !isBuild {
+			return fmt.Errorf("refusing to prune %s: not a build tag", candidate.Tag)
+		}
+		tags[i] = candidate.Tag
+	}
+
+	return withLock(cfg, func() error {
+		if err := git.DeleteTags(tags...); err != nil {
+			return fmt.Errorf("failed to delete local tags: %w", err)
+		}
+		if remote != "" {
+			if err := git.DeleteRemoteTags(remote, tags...); err != nil {
+				return fmt.Errorf("failed to delete tags from %s: %w", remote, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ---------- Helper Functions ----------
+
+// versionLine returns the line of a version that KeepPerMinor counts build tags in: major.minor for
+// semantic versions (e.g. 1.2 for 1.2.3), and the version without its last part for calendar versions
+// (e.g. 2026.10 for 2026.10.3).
+func versionLine(v Version) string {
+	if semver, ok := v.(SemVer); ok {
+		return fmt.Sprintf("%d.%d", semver.Major, semver.Minor)
+	}
+	raw := v.String()
+	last := strings.LastIndexFunc(raw, func(r rune) bool { return r < '0' || r > '9' })
+	return raw[:max(0, last)]
+}
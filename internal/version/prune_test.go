@@ -0,0 +1,105 @@
+package version
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestFindPruneCandidates validates that each retention rule selects the expected build tags
+// and that clean release tags are never selected.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestFindPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tags := []git.TagInfo{
+		{Name: "v1.0.0-aaaaaaa", Date: now.Add(-100 * day)},
+		{Name: "v1.0.1-bbbbbbb", Date: now.Add(-90 * day)},
+		{Name: "v1.0.1", Date: now.Add(-89 * day)},
+		{Name: "v1.1.0-ccccccc", Date: now.Add(-10 * day)},
+		{Name: "v1.1.1-ddddddd", Date: now.Add(-5 * day)},
+		{Name: "v1.1.2-eeeeeee", Date: now.Add(-1 * day)},
+		{Name: "not-a-version", Date: now.Add(-365 * day)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   PrunePolicy
+		expected []string
+	}{
+		{"superseded", PrunePolicy{Superseded: true}, []string{"v1.0.0-aaaaaaa", "v1.0.1-bbbbbbb"}},
+		{"max age", PrunePolicy{MaxAge: 30 * day}, []string{"v1.0.0-aaaaaaa", "v1.0.1-bbbbbbb"}},
+		{"keep per minor", PrunePolicy{KeepPerMinor: 1}, []string{"v1.0.0-aaaaaaa", "v1.1.0-ccccccc", "v1.1.1-ddddddd"}},
+		{"no policy", PrunePolicy{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := FindPruneCandidates(tags, tt.policy, config.Default(), now)
+			if err != nil {
+				t.Fatalf("FindPruneCandidates failed: %v", err)
+			}
+			if len(candidates) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %+v", tt.expected, candidates)
+			}
+			for i, candidate := range candidates {
+				if candidate.Tag != tt.expected[i] {
+					t.Errorf("Candidate %d: expected %s, got %s", i, tt.expected[i], candidate.Tag)
+				}
+			}
+		})
+	}
+}
+
+// TestFindPruneCandidatesScheme validates that build tags are recognized by the configured tag prefix and
+// versioning scheme, and that tags of other prefixes are left alone.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestFindPruneCandidatesScheme(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Default()
+	cfg.Tag.Prefix = "release-"
+	cfg.Versioning.Scheme = SchemeCalVer
+	cfg.Versioning.CalVerFormat = "YYYY.0M.MICRO"
+	tags := []git.TagInfo{
+		{Name: "release-2026.09.0-aaaaaaa", Date: now.Add(-3 * time.Hour)},
+		{Name: "release-2026.09.0", Date: now.Add(-2 * time.Hour)},
+		{Name: "release-2026.10.0-bbbbbbb", Date: now.Add(-1 * time.Hour)},
+		{Name: "release-2026.10.1-ccccccc", Date: now},
+		{Name: "v1.0.0-ddddddd", Date: now.Add(-365 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   PrunePolicy
+		expected []string
+	}{
+		{"superseded", PrunePolicy{Superseded: true}, []string{"release-2026.09.0-aaaaaaa"}},
+		{"max age", PrunePolicy{MaxAge: 90 * time.Minute}, []string{"release-2026.09.0-aaaaaaa"}},
+		{"keep per period", PrunePolicy{KeepPerMinor: 1}, []string{"release-2026.10.0-bbbbbbb"}},
+	}
+	for _, tt := range tests {
+		candidates, err := FindPruneCandidates(tags, tt.policy, cfg, now)
+		if err != nil {
+			t.Fatalf("FindPruneCandidates failed: %v", err)
+		}
+		var pruned []string
+		for _, candidate := range candidates {
+			pruned = append(pruned, candidate.Tag)
+		}
+		if !slices.Equal(pruned, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, pruned)
+		}
+	}
+}
@@ -0,0 +1,559 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/issues"
+	"git-tagger/internal/journal"
+	"git-tagger/internal/logging"
+	"git-tagger/internal/policy"
+	"git-tagger/internal/versionfile"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ---------- Release Functions ----------
+
+// LatestVersion finds the highest semantic version among all tags carrying the configured prefix.
+// Hash-suffixed build tags count as their core version.
+// parameters:
+// - prefix: the prefix of version tags
+// returns:
+// - SemVer: the highest version found
+// - bool: false if no version tags exist
+// - error: an error object if something went wrong, otherwise nil
+func LatestVersion(prefix string) (SemVer, bool, error) {
+	_, latest, found, err := LatestSchemeVersion(SemVerScheme{}, prefix)
+	if err != nil || !found {
+		return SemVer{}, found, err
+	}
+	return latest.(SemVer), true, nil
+}
+
+// LatestSchemeVersion finds the highest version of a versioning scheme among all tags carrying the configured prefix.
+// Hash-suffixed build tags count as their core version.
+// parameters:
+// - scheme: the versioning scheme
+// - prefix: the prefix of version tags
+// returns:
+// - string: the name of the tag
+// - Version: the highest version found, or the initial version of the scheme if no version tags exist
+// - bool: false if no version tags exist
+// - error: an error object if something went wrong, otherwise nil
+func LatestSchemeVersion(scheme Scheme, prefix string) (string, Version, bool, error) {
+	tags, err := git.RunGitCommand("tag", "--list", prefix+"*")
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+	tag, latest, found := highestVersion(scheme, tags, prefix)
+	return tag, latest, found, nil
+}
+
+// CurrentVersion finds the highest version tag reachable from a revision.
+// parameters:
+// - rev: the revision to start from (e.g. HEAD)
+// - scheme: the versioning scheme
+// - prefix: the prefix of version tags
+// returns:
+// - string: the name of the tag
+// - Version: the version of the tag, with a hash suffix reported as build metadata
+// - error: git.ErrNoTags if no version tag is reachable, otherwise an error object if something went wrong
+func CurrentVersion(rev string, scheme Scheme, prefix string) (string, Version, error) {
+	tags, err := git.RunGitCommand("tag", "--merged", rev, "--list", prefix+"*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to retrieve tags reachable from %s: %w", rev, err)
+	}
+
+	tag, v, found := highestVersion(scheme, tags, prefix)
+	if !found {
+		return "", nil, fmt.Errorf("failed to find the current version of %s: %w", rev, git.ErrNoTags)
+	}
+
+	if _, isBuild, _ := parseTag(scheme, tag, prefix); isBuild {
+		v = v.withBuild(buildSuffixPattern.FindStringSubmatch(tag)[2])
+	}
+	return tag, v, nil
+}
+
+// PreviousTag finds the version tag preceding a tag in its history. Release tags are preceded by the
+// previous release (skipping pre-releases for stable versions); build tags by the previous tag of any kind.
+// parameters:
+// - tag: the tag to find the predecessor of
+// - cfg: the repository configuration
+// returns:
+// - string: the preceding tag, or empty if the tag is the first version
+// - error: an error object if the tag is not a version tag or something went wrong, otherwise nil
+func PreviousTag(tag string, cfg *config.Config) (string, error) {
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return "", err
+	}
+	target, targetIsBuild, err := parseTag(scheme, tag, cfg.Tag.Prefix)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := git.RunGitCommand("tag", "--merged", tag, "--list", cfg.Tag.Prefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve tags reachable from %s: %w", tag, err)
+	}
+
+	var previousTag string
+	var previous Version
+	for _, candidate := range tags {
+		v, isBuild, err := parseTag(scheme, candidate, cfg.Tag.Prefix)
+		if err != nil || scheme.Compare(v, target) >= 0 || (isBuild && !targetIsBuild) {
+			continue
+		}
+		if t, ok := target.(SemVer); ok && t.Pre == "" && !targetIsBuild && v.(SemVer).Pre != "" {
+			continue
+		}
+
+		if previous == nil || scheme.Compare(v, previous) > 0 {
+			previousTag, previous = candidate, v
+		}
+	}
+	return previousTag, nil
+}
+
+// FormatVersion renders a version through a text/template. The template can use the fields
+// of the version (.Major, .Minor, .Patch, .Pre, .Build, and .Year, .Month, .Week, .Day, .Micro
+// for calendar versions), .Prefix, .Version (without prefix) and .Tag.
+// parameters:
+// - v: the version to format
+// - prefix: the prefix of version tags
+// - format: the template to render, e.g. "{{.Major}}.{{.Minor}}"
+// returns:
+// - string: the rendered version
+// - error: an error object if the template is invalid, otherwise nil
+func FormatVersion(v Version, prefix, format string) (string, error) {
+	return formatVersion(v, prefix, format, nil)
+}
+
+// formatVersion renders a version like FormatVersion, with additional template fields.
+func formatVersion(v Version, prefix, format string, extra map[string]any) (string, error) {
+	tmpl, err := template.New("version").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+
+	data := v.Fields()
+	data["Prefix"] = prefix
+	data["Version"] = v.String()
+	data["Tag"] = v.Tag(prefix)
+	maps.Copy(data, extra)
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to format version: %w", err)
+	}
+	return out.String(), nil
+}
+
+// ParseVersionArg parses a version given on the command line, with or without the tag prefix.
+// parameters:
+// - arg: the version to parse (e.g. "2.0.0" or "v2.0.0")
+// - prefix: the prefix of version tags
+// returns:
+// - SemVer: the parsed version
+// - error: an error object if the argument is not a semantic version, otherwise nil
+func ParseVersionArg(arg, prefix string) (SemVer, error) {
+	if v, err := ParseSemVer(arg, prefix); err == nil {
+		return v, nil
+	}
+	return ParseSemVer(arg, "")
+}
+
+// BumpVersion computes the next version by explicitly bumping the latest version.
+// parameters:
+// - level: the level of version increment (major, minor, patch, prerelease)
+// - cfg: the repository configuration
+// returns:
+// - SemVer: the bumped version
+// - error: an error object if something went wrong, otherwise nil
+func BumpVersion(level string, cfg *config.Config) (SemVer, error) {
+	if err := requireSemVer(cfg); err != nil {
+		return SemVer{}, err
+	}
+	latest, _, line, err := latestReleaseVersion(cfg)
+	if err != nil {
+		return SemVer{}, err
+	}
+	// Explicit bumps are never capped, so that the tagged version is the one asked for
+	if line != nil {
+		if _, err := line.Constrain(level, MaintenanceReject); err != nil {
+			return SemVer{}, err
+		}
+	}
+
+	// Leaving initial development is an explicit decision under the non-strict policies
+	policy, err := zeroPolicy(cfg.Versioning.ZeroPolicy)
+	if err != nil {
+		return SemVer{}, err
+	}
+	if level == "major" && latest.Major == 0 && policy != ZeroPolicyStrict {
+		return SemVer{}, fmt.Errorf("refusing to bump %s to a major version during initial development: use promote 1.0.0", latest.Tag(cfg.Tag.Prefix))
+	}
+	return latest.Bump(level, cfg.Tag.PrereleaseID)
+}
+
+// TagRelease tags a commit with the given version, refusing to duplicate an existing tag
+// or to go backwards from the latest version unless forced. The branch policy and maintenance
+// line are those of the current branch, which must contain the commit. The tag is recorded in a run
+// journal so that it can be reverted with undo, and created while holding the repository lock.
+// parameters:
+// - next: the version to tag
+// - commit: the revision to tag
+// - cfg: the repository configuration
+// - force: whether to replace existing tags and allow lower versions
+// returns:
+// - string: the name of the created tag
+// - error: an error object if something went wrong, otherwise nil
+func TagRelease(next SemVer, commit string, cfg *config.Config, force bool) (string, error) {
+	if err := requireSemVer(cfg); err != nil {
+		return "", err
+	}
+	kind := policy.KindRelease
+	if next.Pre != "" {
+		kind = policy.KindPrerelease
+	}
+	// The release commit and tag are created on the current branch, so its policy applies
+	branch, err := policy.AllowCommit(commit, "", cfg.Branches, kind)
+	if err != nil {
+		return "", err
+	}
+	var line *Line
+	if branch != "" {
+		if line, err = MaintenanceLine(branch, cfg); err != nil {
+			return "", err
+		}
+	}
+	if line != nil && !line.Contains(next) {
+		return "", fmt.Errorf("%w: %s does not belong to %s on %s", ErrOutsideLine, next.Tag(cfg.Tag.Prefix), line, line.Branch)
+	}
+
+	var tag string
+	err = withLock(cfg, func() error {
+		var err error
+		tag, err = tagRelease(next, commit, cfg, force)
+		return err
+	})
+	return tag, err
+}
+
+// tagRelease tags a commit with a version while the repository lock is held.
+// parameters:
+// - next: the version to tag
+// - commit: the revision to tag
+// - cfg: the repository configuration
+// - force: whether to replace existing tags and allow lower versions
+// returns:
+// - string: the name of the created tag
+// - error: an error object if something went wrong, otherwise nil
+func tagRelease(next SemVer, commit string, cfg *config.Config, force bool) (string, error) {
+	if next.IsHashBuild() {
+		return "", fmt.Errorf("refusing to create release %s: pre-release looks like a commit hash", next)
+	}
+	tag := next.Tag(cfg.Tag.Prefix)
+
+	if !force {
+		if git.TagExists(tag) {
+			return "", fmt.Errorf("%w: %s (use -force to replace it)", git.ErrTagExists, tag)
+		}
+
+		latest, found, _, err := latestReleaseVersion(cfg)
+		if err != nil && !errors.Is(err, git.ErrNoTags) {
+			return "", err
+		}
+		if found && next.Compare(latest) < 0 {
+			return "", fmt.Errorf("version %s is lower than the latest version %s (use -force to tag it anyway)", tag, latest.Tag(cfg.Tag.Prefix))
+		}
+	}
+
+	hash, err := git.ResolveCommit(commit)
+	if err != nil {
+		return "", err
+	}
+
+	referenced, err := IssuesSinceRelease(hash, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep the tag a forced release replaces, so that undo can restore it
+	var replaced string
+	if force && git.TagExists(tag) {
+		if replaced, err = git.GetTagObject(tag); err != nil {
+			return "", err
+		}
+	}
+
+	branch, err := runBranch()
+	if err != nil {
+		return "", err
+	}
+	run, err := journal.Start(branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to start run journal: %w", err)
+	}
+
+	// Rewrite version files and tag the release commit holding them
+	released := hash
+	if hash, err = PrepareRelease(next, released, cfg); err != nil {
+		return "", errors.Join(err, run.Finish(journal.StatusRolledBack))
+	}
+	if hash != released {
+		if err := run.RecordCommit(hash, released); err != nil {
+			return "", rollbackRun(run, err)
+		}
+	}
+
+	opts := git.TagOptions{Sign: cfg.Tag.Sign, Force: force}
+	if err := run.RecordReplacement(tag, hash, replaced); err != nil {
+		return "", rollbackRun(run, err)
+	}
+	if err := git.CreateTagWithOptions(tag, annotation(fmt.Sprintf("Release %s", tag), referenced), hash, opts); err != nil {
+		return "", rollbackRun(run, fmt.Errorf("failed to create tag %s: %w", tag, err))
+	}
+	if err := run.Confirm(tag); err != nil {
+		return "", rollbackRun(run, err)
+	}
+	if err := pushRun(run, cfg); err != nil {
+		return "", err
+	}
+	if err := run.Finish(journal.StatusCompleted); err != nil {
+		return "", err
+	}
+	return tag, nil
+}
+
+// IssuesSinceRelease collects the issues referenced by the commits since the latest version tag reachable from a commit.
+// parameters:
+// - commit: the commit to collect issues up to
+// - cfg: the repository configuration
+// returns:
+// - []string: the referenced issues, in order of first appearance
+// - error: an error object if something went wrong, otherwise nil
+func IssuesSinceRelease(commit string, cfg *config.Config) ([]string, error) {
+	scheme, err := NewScheme(cfg, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	extractor, err := issues.NewExtractor(cfg.Issues)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := git.RunGitCommand("tag", "--merged", commit, "--list", cfg.Tag.Prefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tags reachable from %s: %w", commit, err)
+	}
+	from, _, _ := highestVersion(scheme, tags, cfg.Tag.Prefix)
+
+	commits, err := git.GetCommitsBetween(from, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var referenced []string
+	for _, details := range commits {
+		for _, issue := range extractor.Extract(details) {
+			if !slices.Contains(referenced, issue) {
+				referenced = append(referenced, issue)
+			}
+		}
+	}
+	return referenced, nil
+}
+
+// PrepareRelease rewrites the configured version files for a release and, unless disabled, commits them
+// together with the configured release paths as a release commit on top of the released commit. If it
+// fails, the version files already rewritten are restored. Callers record a created release commit in
+// their run journal so that it is reset when the run is rolled back.
+// parameters:
+// - next: the version being released
+// - commit: the full hash of the commit being released, which must be HEAD if release files are configured
+// - cfg: the repository configuration
+// returns:
+// - string: the commit to tag, which is the release commit if one was created
+// - error: git.ErrDirtyTree if a version file has uncommitted changes, otherwise an error object if something went wrong
+func PrepareRelease(next Version, commit string, cfg *config.Config) (_ string, err error) {
+	release := cfg.Release
+	if len(release.Files) == 0 && (len(release.Paths) == 0 || !release.Commit) {
+		return commit, nil
+	}
+
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return "", err
+	}
+	if commit != head {
+		return "", fmt.Errorf("release files can only be updated when releasing HEAD, not %s", commit)
+	}
+
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return "", err
+	}
+
+	// Local edits of version files would end up in the release commit
+	if release.Commit {
+		var dirty []string
+		for _, file := range release.Files {
+			changed, err := git.ChangedPaths(filepath.Join(root, file.Path))
+			if err != nil {
+				return "", err
+			}
+			if len(changed) > 0 {
+				dirty = append(dirty, file.Path)
+			}
+		}
+		if len(dirty) > 0 {
+			return "", fmt.Errorf("%w: commit or stash the changes to %s before releasing", git.ErrDirtyTree, strings.Join(dirty, ", "))
+		}
+	}
+
+	// Put back the files rewritten so far if the release cannot be prepared
+	originals := map[string][]byte{}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, restoreFiles(originals))
+		}
+	}()
+
+	var changed []string
+	for _, file := range release.Files {
+		format := file.Format
+		if format == "" {
+			format = "{{.Version}}"
+		}
+		value, err := FormatVersion(next, cfg.Tag.Prefix, format)
+		if err != nil {
+			return "", fmt.Errorf("failed to format version for %s: %w", file.Path, err)
+		}
+
+		path := filepath.Join(root, file.Path)
+		if _, saved := originals[path]; !saved {
+			if originals[path], err = os.ReadFile(path); err != nil {
+				return "", fmt.Errorf("failed to read version file %s: %w", file.Path, err)
+			}
+		}
+		updated, err := versionfile.Update(root, file, value)
+		if err != nil {
+			return "", err
+		}
+		if updated {
+			logging.Logger().Info("Updated version file", "path", file.Path, "version", value)
+			changed = append(changed, filepath.Join(root, file.Path))
+		}
+	}
+
+	if !release.Commit {
+		return commit, nil
+	}
+
+	// Include files regenerated by the build, if they changed
+	paths := make([]string, len(release.Paths))
+	for i, path := range release.Paths {
+		paths[i] = filepath.Join(root, path)
+	}
+	regenerated, err := git.ChangedPaths(paths...)
+	if err != nil {
+		return "", err
+	}
+	changed = append(changed, regenerated...)
+	if len(changed) == 0 {
+		return commit, nil
+	}
+
+	message, err := FormatVersion(next, cfg.Tag.Prefix, release.CommitMessage)
+	if err != nil {
+		return "", fmt.Errorf("failed to format release commit message: %w", err)
+	}
+	releaseCommit, err := git.CreateCommit(message, git.CommitOptions{
+		Paths:     changed,
+		Author:    git.Identity{Name: release.Author.Name, Email: release.Author.Email},
+		Committer: git.Identity{Name: release.Committer.Name, Email: release.Committer.Email},
+	})
+	if err != nil {
+		return "", err
+	}
+	logging.Logger().Info("Created release commit", "commit", releaseCommit, "message", message)
+	return releaseCommit, nil
+}
+
+// ---------- Helper Functions ----------
+
+// restoreFiles writes back the original contents of rewritten files.
+func restoreFiles(originals map[string][]byte) error {
+	var errs []error
+	for path, content := range originals {
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// highestVersion returns the highest version among the given tags, counting build tags as their core version.
+// When a release and a build tag share a core version, the release wins. If no tag is a version of the
+// scheme, the initial version of the scheme is returned.
+func highestVersion(scheme Scheme, tags []string, prefix string) (string, Version, bool) {
+	var latestTag string
+	latest := scheme.Initial()
+	found := false
+	for _, tag := range tags {
+		v, isBuild, err := parseTag(scheme, tag, prefix)
+		if err != nil {
+			continue
+		}
+
+		c := scheme.Compare(v, latest)
+		if !found || c > 0 || (c == 0 && !isBuild) {
+			latestTag, latest, found = tag, v, true
+		}
+	}
+	return latestTag, latest, found
+}
+
+// latestReleaseVersion finds the latest semantic version of the current branch: within the version line on
+// maintenance branches, where a missing version tag is reported as git.ErrNoTags, and among all tags otherwise.
+func latestReleaseVersion(cfg *config.Config) (SemVer, bool, *Line, error) {
+	line, err := MaintenanceLine("", cfg)
+	if err != nil {
+		return SemVer{}, false, nil, err
+	}
+	if line == nil {
+		latest, found, err := LatestVersion(cfg.Tag.Prefix)
+		return latest, found, nil, err
+	}
+	_, latest, err := line.Latest(cfg.Tag.Prefix)
+	return latest, err == nil, line, err
+}
+
+// runBranch returns the branch recorded in the journal of a run that tags the checked-out commit.
+// returns:
+// - string: the current branch, or "(detached HEAD)"
+// - error: an error object if something went wrong, otherwise nil
+func runBranch() (string, error) {
+	branch, err := git.GetCurrentBranch()
+	if errors.Is(err, git.ErrDetachedHead) {
+		return "(detached HEAD)", nil
+	}
+	return branch, err
+}
+
+// requireSemVer rejects manual semantic version releases in repositories using another versioning scheme.
+func requireSemVer(cfg *config.Config) error {
+	if scheme := cfg.Versioning.Scheme; scheme != "" && scheme != SchemeSemVer {
+		return fmt.Errorf("manual semantic version releases are not supported with the %s versioning scheme", scheme)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package version
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/journal"
+	"git-tagger/internal/testutils"
+	"os"
+	"slices"
+	"testing"
+)
+
+// TestReleaseRollback validates that a release failing to update its version files leaves them unchanged,
+// and that rolling back a release run deletes its tag and resets its release commit.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestReleaseRollback(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	testutils.CreateAndCommitFile(t, "VERSION", "chore: add version file")
+	testutils.CreateAndCommitFile(t, "package.json", "chore: add package file")
+	head := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD")
+
+	cfg := config.Default()
+	cfg.Release.Commit = true
+	cfg.Release.Files = []config.VersionFile{{Path: "VERSION"}, {Path: "package.json", Type: "json"}}
+
+	// package.json holds no JSON, so the release fails after VERSION was rewritten
+	if _, err := TagRelease(SemVer{Major: 1}, "HEAD", cfg, false); err == nil {
+		t.Fatalf("Expected the release to fail on package.json")
+	}
+	if content, _ := os.ReadFile("VERSION"); string(content) != "content" {
+		t.Errorf("Expected VERSION to be restored, got %q", content)
+	}
+	if current := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD"); current != head {
+		t.Errorf("Expected no release commit, got HEAD %s", current)
+	}
+
+	cfg.Release.Files = cfg.Release.Files[:1]
+	tag, err := TagRelease(SemVer{Major: 1}, "HEAD", cfg, false)
+	if err != nil {
+		t.Fatalf("TagRelease failed: %v", err)
+	}
+	if current := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD"); current == head {
+		t.Fatalf("Expected a release commit on top of %s", head)
+	}
+
+	run, err := journal.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if err := run.Rollback(""); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if git.TagExists(tag) {
+		t.Errorf("Expected %s to be deleted", tag)
+	}
+	if current := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD"); current != head {
+		t.Errorf("Expected the release commit to be reset to %s, got %s", head, current)
+	}
+	if content, _ := os.ReadFile("VERSION"); string(content) != "content" {
+		t.Errorf("Expected VERSION to be reset with the release commit, got %q", content)
+	}
+}
+
+// TestApplyPlanPush validates that automated tags are annotated with the configured message and pushed to the
+// configured remote, and that a failed push rolls the run back.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestApplyPlanPush(t *testing.T) {
+	remote := t.TempDir()
+	testutils.SetupTestRepo(t)
+	for _, args := range [][]string{
+		{"init", "-q", "--bare", remote},
+		{"remote", "add", "origin", remote},
+		{"tag", "v1.0.0"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: crash"},
+	} {
+		if err := testutils.RunGitCommand(args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	head := testutils.RunGitCommandAndGetOutput(t, "rev-parse", "HEAD")
+
+	cfg := config.Default()
+	cfg.Tag.Message = "Build {{.Tag}} of {{.Commit}}"
+	cfg.Git.PushTags = true
+	cfg.Git.RemoteName = "nowhere"
+
+	plan, err := PlanUntaggedCommits("HEAD", cfg)
+	if err != nil || len(plan.Tags) != 1 {
+		t.Fatalf("Expected one tag to be planned, got %+v (%v)", plan, err)
+	}
+	tag := plan.Tags[0].Tag
+	if _, err := ApplyPlan(plan, cfg); err == nil {
+		t.Fatalf("Expected pushing to a missing remote to fail")
+	}
+	if git.TagExists(tag) {
+		t.Errorf("Expected %s to be rolled back after the failed push", tag)
+	}
+
+	cfg.Git.RemoteName = "origin"
+	if _, err := ApplyPlan(plan, cfg); err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+	if tags, err := git.ListRemoteTags("origin"); err != nil || !slices.Contains(tags, tag) {
+		t.Errorf("Expected %s to be pushed to origin, got %v (%v)", tag, tags, err)
+	}
+	expected := "Build " + tag + " of " + head
+	if message := testutils.RunGitCommandAndGetOutput(t, "tag", "-l", "--format=%(contents:subject)", tag); message != expected {
+		t.Errorf("Expected the annotation %q, got %q", expected, message)
+	}
+}
@@ -0,0 +1,103 @@
+package version
+
+import (
+	"fmt"
+	"git-tagger/internal/config"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	SchemeSemVer = "semver" // semantic versioning (MAJOR.MINOR.PATCH)
+	SchemeCalVer = "calver" // calendar versioning (e.g. YYYY.0M.MICRO)
+)
+
+// buildSuffixPattern matches the commit hash suffix of per-commit build tags.
+var buildSuffixPattern = regexp.MustCompile(`^(.+)-([0-9a-f]{7,40})$`)
+
+// Version is a version of some versioning scheme.
+type Version interface {
+	String() string           // the version without prefix
+	Tag(prefix string) string // the version as a tag name
+	Fields() map[string]any   // named parts of the version, for output templates
+	withBuild(hash string) Version
+}
+
+// Scheme parses, orders and increments the versions of a versioning scheme.
+type Scheme interface {
+	Name() string
+	Parse(raw string) (Version, error)                   // parses a version without prefix
+	Compare(a, b Version) int                            // orders two versions of this scheme
+	Initial() Version                                    // the version to start from when there are no tags
+	Next(current Version, level string) (Version, error) // increments a version for a change of the given level
+}
+
+// ---------- Scheme Functions ----------
+
+// NewScheme creates the versioning scheme selected in the configuration.
+// parameters:
+// - cfg: the repository configuration
+// - now: the clock used by calendar-based schemes
+// returns:
+// - Scheme: the configured versioning scheme
+// - error: an error object if the scheme or its format is invalid, otherwise nil
+func NewScheme(cfg *config.Config, now func() time.Time) (Scheme, error) {
+	switch cfg.Versioning.Scheme {
+	case SchemeSemVer, "":
+		return SemVerScheme{PrereleaseID: cfg.Tag.PrereleaseID}, nil
+	case SchemeCalVer:
+		return NewCalVerScheme(cfg.Versioning.CalVerFormat, now)
+	}
+	return nil, fmt.Errorf("unknown versioning scheme %q: expected %s or %s", cfg.Versioning.Scheme, SchemeSemVer, SchemeCalVer)
+}
+
+// SemVerScheme is the semantic versioning scheme.
+type SemVerScheme struct {
+	PrereleaseID string // identifier of new pre-releases (e.g. "rc")
+}
+
+// Name returns the name of the scheme.
+func (s SemVerScheme) Name() string { return SchemeSemVer }
+
+// Parse parses a semantic version without prefix.
+func (s SemVerScheme) Parse(raw string) (Version, error) { return ParseSemVer(raw, "") }
+
+// Compare orders two semantic versions by precedence.
+func (s SemVerScheme) Compare(a, b Version) int { return a.(SemVer).Compare(b.(SemVer)) }
+
+// Initial returns 0.0.0.
+func (s SemVerScheme) Initial() Version { return SemVer{} }
+
+// Next bumps a semantic version by the given level.
+func (s SemVerScheme) Next(current Version, level string) (Version, error) {
+	return current.(SemVer).Bump(level, s.PrereleaseID)
+}
+
+// ---------- Helper Functions ----------
+
+// parseTag parses a version tag of a scheme. Per-commit build tags (with a commit hash
+// suffix) are parsed as their core version and reported as builds.
+// parameters:
+// - scheme: the versioning scheme
+// - tag: the tag to parse
+// - prefix: the prefix of version tags
+// returns:
+// - Version: the parsed version
+// - bool: true if the tag is a build tag
+// - error: an error object if the tag is not a version of the scheme, otherwise nil
+func parseTag(scheme Scheme, tag, prefix string) (Version, bool, error) {
+	raw, found := strings.CutPrefix(tag, prefix)
+	if !found {
+		return nil, false, fmt.Errorf("invalid version format: %s (missing prefix %q)", tag, prefix)
+	}
+
+	if m := buildSuffixPattern.FindStringSubmatch(raw); m != nil {
+		if v, err := scheme.Parse(m[1]); err == nil {
+			return v, true, nil
+		}
+	}
+
+	v, err := scheme.Parse(raw)
+	return v, false, err
+}
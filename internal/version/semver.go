@@ -0,0 +1,205 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	semVerPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+	hashPattern   = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
+// SemVer is a parsed semantic version (https://semver.org).
+type SemVer struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string // pre-release identifiers without the leading '-' (e.g. "rc.1")
+	Build string // build metadata without the leading '+'
+}
+
+// ---------- SemVer Functions ----------
+
+// ParseSemVer parses a version tag carrying the given prefix (e.g. "v1.2.3-rc.1").
+// parameters:
+// - tag: the tag to parse
+// - prefix: the tag prefix to strip before parsing
+// returns:
+// - SemVer: the parsed version
+// - error: an error object if the tag is not a semantic version, otherwise nil
+func ParseSemVer(tag, prefix string) (SemVer, error) {
+	raw, found := strings.CutPrefix(tag, prefix)
+	if !found {
+		return SemVer{}, fmt.Errorf("invalid version format: %s (missing prefix %q)", tag, prefix)
+	}
+
+	m := semVerPattern.FindStringSubmatch(raw)
+	if m == nil {
+		return SemVer{}, fmt.Errorf("invalid version format: %s", tag)
+	}
+
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return SemVer{Major: major, Minor: minor, Patch: patch, Pre: m[4], Build: m[5]}, nil
+}
+
+// String formats the version without a prefix.
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Tag formats the version as a tag name with the given prefix.
+func (v SemVer) Tag(prefix string) string {
+	return prefix + v.String()
+}
+
+// Fields returns the parts of the version for output templates.
+func (v SemVer) Fields() map[string]any {
+	return map[string]any{"Major": v.Major, "Minor": v.Minor, "Patch": v.Patch, "Pre": v.Pre, "Build": v.Build}
+}
+
+// withBuild returns the version with the given build metadata.
+func (v SemVer) withBuild(hash string) Version {
+	v.Build = hash
+	return v
+}
+
+// Core returns the version without pre-release and build parts.
+func (v SemVer) Core() SemVer {
+	return SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// IsHashBuild reports whether the pre-release part is a commit hash, as used by
+// the per-commit build tags created when tagging untagged commits.
+func (v SemVer) IsHashBuild() bool {
+	return hashPattern.MatchString(v.Pre)
+}
+
+// Compare compares two versions by semantic version precedence, ignoring build metadata.
+// parameters:
+// - other: the version to compare against
+// returns:
+// - int: -1 if v is lower than other, 1 if it is higher, 0 if both are equal
+func (v SemVer) Compare(other SemVer) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePre(v.Pre, other.Pre)
+}
+
+// Bump increments the version by the given level.
+// A pre-release is released by bumping to the level it is a pre-release of,
+// e.g. 1.3.0-rc.2 bumped by minor becomes 1.3.0 rather than 1.4.0.
+// parameters:
+// - level: the level of version increment (major, minor, patch, prerelease)
+// - preID: the identifier used when starting a new pre-release (e.g. "rc")
+// returns:
+// - SemVer: the incremented version
+// - error: an error object if the level is unknown, otherwise nil
+func (v SemVer) Bump(level, preID string) (SemVer, error) {
+	next := v.Core()
+	isPre := v.Pre != "" && !v.IsHashBuild()
+
+	switch level {
+	case "major":
+		if !isPre || v.Minor != 0 || v.Patch != 0 {
+			next = SemVer{Major: v.Major + 1}
+		}
+	case "minor":
+		if !isPre || v.Patch != 0 {
+			next = SemVer{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case "patch":
+		if !isPre {
+			next.Patch++
+		}
+	case "prerelease":
+		if isPre {
+			next.Pre = incrementPre(v.Pre)
+		} else {
+			next.Patch++
+			next.Pre = preID + ".1"
+		}
+	default:
+		return SemVer{}, fmt.Errorf("unknown version increment level: %s", level)
+	}
+	return next, nil
+}
+
+// ---------- Helper Functions ----------
+
+// comparePre compares pre-release strings following the semver precedence rules:
+// a version without pre-release is higher, numeric identifiers compare numerically
+// and are lower than alphanumeric ones, and a shorter identifier list is lower.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	}
+	return 0
+}
+
+// incrementPre increments the last numeric identifier of a pre-release,
+// appending ".1" if there is none (e.g. rc.1 -> rc.2, beta -> beta.1).
+func incrementPre(pre string) string {
+	parts := strings.Split(pre, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if n, err := strconv.Atoi(parts[i]); err == nil {
+			parts[i] = strconv.Itoa(n + 1)
+			return strings.Join(parts, ".")
+		}
+	}
+	return pre + ".1"
+}
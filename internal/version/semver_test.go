@@ -0,0 +1,124 @@
+package version
+
+import (
+	"git-tagger/internal/config"
+	"git-tagger/internal/testutils"
+	"testing"
+)
+
+// TestSemVerBump validates explicit bumps, including releasing and incrementing pre-releases.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestSemVerBump(t *testing.T) {
+	tests := []struct {
+		current  string
+		level    string
+		expected string
+	}{
+		{"v1.2.3", "major", "v2.0.0"},
+		{"v1.2.3", "minor", "v1.3.0"},
+		{"v1.2.3", "patch", "v1.2.4"},
+		{"v1.2.3", "prerelease", "v1.2.4-rc.1"},
+		{"v1.2.4-rc.1", "prerelease", "v1.2.4-rc.2"},
+		{"v1.2.4-rc.1", "patch", "v1.2.4"},
+		{"v1.3.0-rc.1", "minor", "v1.3.0"},
+		{"v1.3.1-rc.1", "minor", "v1.4.0"},
+		{"v2.0.0-beta", "major", "v2.0.0"},
+		{"v1.2.3-1a2b3c4", "patch", "v1.2.4"},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseSemVer(tt.current, "v")
+		if err != nil {
+			t.Fatalf("ParseSemVer(%s) failed: %v", tt.current, err)
+		}
+		next, err := v.Bump(tt.level, "rc")
+		if err != nil {
+			t.Fatalf("Bump(%s, %s) failed: %v", tt.current, tt.level, err)
+		}
+		if got := next.Tag("v"); got != tt.expected {
+			t.Errorf("Bump(%s, %s): expected %s, got %s", tt.current, tt.level, tt.expected, got)
+		}
+	}
+}
+
+// TestSemVerCompare validates semantic version precedence, including pre-release ordering.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestSemVerCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseSemVer(ordered[i], "")
+		b, _ := ParseSemVer(ordered[i+1], "")
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	if _, err := ParseSemVer("release-1.2", "release-"); err == nil {
+		t.Errorf("Expected an error for an incomplete version")
+	}
+}
+
+// TestInitialDevelopmentLevel validates how each 0.x policy adjusts increments before 1.0.0.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestInitialDevelopmentLevel(t *testing.T) {
+	zero := SemVer{Minor: 4}
+	stable := SemVer{Major: 1, Minor: 4}
+
+	tests := []struct {
+		current  SemVer
+		level    string
+		policy   string
+		expected string
+	}{
+		{zero, "major", ZeroPolicyBreakingMinor, "minor"},
+		{zero, "minor", ZeroPolicyBreakingMinor, "minor"},
+		{zero, "major", ZeroPolicyFeatPatch, "minor"},
+		{zero, "minor", ZeroPolicyFeatPatch, "patch"},
+		{zero, "patch", ZeroPolicyFeatPatch, "patch"},
+		{zero, "major", ZeroPolicyStrict, "major"},
+		{zero, "major", "", "minor"}, // an empty policy is the default breaking-minor
+		{stable, "major", ZeroPolicyFeatPatch, "major"},
+		{stable, "minor", ZeroPolicyFeatPatch, "minor"},
+	}
+
+	for _, tt := range tests {
+		got, err := InitialDevelopmentLevel(tt.current, tt.level, tt.policy)
+		if err != nil {
+			t.Fatalf("InitialDevelopmentLevel failed: %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("InitialDevelopmentLevel(%s, %s, %s): expected %s, got %s", tt.current, tt.level, tt.policy, tt.expected, got)
+		}
+	}
+
+	if _, err := InitialDevelopmentLevel(zero, "major", "bogus"); err == nil {
+		t.Errorf("Expected an error for an unknown policy")
+	}
+
+	// An explicit major bump is judged by the same default as planning
+	testutils.SetupTestRepo(t)
+	if err := testutils.RunGitCommand("tag", "v0.4.0"); err != nil {
+		t.Fatalf("git tag failed: %v", err)
+	}
+	cfg := config.Default()
+	cfg.Versioning.ZeroPolicy = ""
+	if _, err := BumpVersion("major", cfg); err == nil {
+		t.Errorf("Expected bump major during initial development to be refused without a configured policy")
+	}
+}
@@ -1,143 +1,79 @@
 package version
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"git-tagger/internal/config"
 	"git-tagger/internal/git"
-	"git-tagger/internal/utils"
-	"log"
-	"strconv"
-	"strings"
+	"git-tagger/internal/lock"
+	"git-tagger/internal/logging"
 )
 
 // ---------- Version Functions ----------
 
-// IncrementVersion increments a semantic version based on a single level.
+// UpdateUntaggedCommits finds untagged commits on a branch, checking tags and messages for version references.
+// The run holds the repository lock; if a conflicting tag appears anyway (e.g. created by an older
+// version of the tool), the versions are recomputed up to cfg.Lock.Retries times.
 // parameters:
-// - latestTag: the current latest semantic version tag
-// - level: the level of version increment (major, minor, patch)
+// - branch: the branch from which to find untagged commits
+// - cfg: the repository configuration
 // returns:
-// - string: the new incremented version tag
 // - error: an error object if something went wrong, otherwise nil
-func IncrementVersion(latestTag string, level string) (string, error) {
-	// Validate the format of the latestTag
-	if !utils.IsSemVer(latestTag) {
-		return "", fmt.Errorf("invalid version format: %s", latestTag)
-	}
-
-	parts := strings.Split(strings.TrimPrefix(latestTag, "v"), ".")
-	if len(parts) != 3 {
-		return "", fmt.Errorf("invalid version format: %s", latestTag)
-	}
-
-	major, _ := strconv.Atoi(parts[0])
-	minor, _ := strconv.Atoi(parts[1])
-	patch, _ := strconv.Atoi(parts[2])
-
-	// Increment the version based on the level determined
-	switch level {
-	case "major":
-		major++
-		minor = 0
-		patch = 0
-	case "minor":
-		minor++
-		patch = 0
-	case "patch":
-		patch++
-	default:
-		return "", fmt.Errorf("unknown version increment level: %s", level)
-	}
-
-	newTag := fmt.Sprintf("v%d.%d.%d", major, minor, patch)
-	return newTag, nil
+func UpdateUntaggedCommits(branch string, cfg *config.Config) error {
+	return withLock(cfg, func() error {
+		for attempt := 1; ; attempt++ {
+			err := tagUntaggedCommits(branch, cfg)
+			if !errors.Is(err, git.ErrTagExists) || attempt > cfg.Lock.Retries {
+				return err
+			}
+			logging.Logger().Warn("A conflicting tag appeared, recomputing versions", "error", err)
+		}
+	})
 }
 
-// UpdateUntaggedCommits finds untagged commits on a branch, checking tags and messages for version references.
+// tagUntaggedCommits plans and creates the tags of the untagged commits on a branch.
 // parameters:
 // - branch: the branch from which to find untagged commits
+// - cfg: the repository configuration
 // returns:
 // - error: an error object if something went wrong, otherwise nil
-func UpdateUntaggedCommits(branch string) error {
-	// Find all untagged commits
-	untaggedCommits, err := git.FindUntagged(branch)
+func tagUntaggedCommits(branch string, cfg *config.Config) error {
+	plan, err := PlanUntaggedCommits(branch, cfg)
 	if err != nil {
-		return utils.WrapErrorf("failed to find untagged commits: %w", err)
+		return err
 	}
 
-	if len(untaggedCommits) == 0 {
-		fmt.Println("No untagged commits found.")
+	if len(plan.Tags) == 0 && len(plan.Skipped) == 0 {
+		logging.Logger().Info("No untagged commits found.")
 		return nil
 	}
 
-	// Find the latest tag on the branch (if any)
-	latestTag, err := git.GetLatestTag()
-	if err != nil {
-		// No tags found; start from v0.0.0 directly
-		log.Printf("No tags found on the branch. Starting from v0.0.0.")
-		latestTag = "v0.0.0"
+	if len(plan.Tags) == 0 {
+		logging.Logger().Info("No release needed: the untagged commits do not warrant a version bump.", "commits", len(plan.Skipped))
+		return nil
 	}
 
-	// Strip any hash suffix to get the core version for incrementing
-	coreVersion := utils.StripHashSuffix(latestTag)
-
-	// Track the current version for tagging
-	currentTag := coreVersion
-
-	// Now proceed to tag all untagged commits from the oldest to the most recent
-	for _, commit := range untaggedCommits { // Get the commit message
-		message, err := git.GetCommitMessage(commit)
-		if err != nil {
-			return fmt.Errorf("failed to retrieve commit message for %s: %w", commit, err)
-		}
-
-		// Determine the increment level based on the commit message
-		incrementLevel := determineIncrementLevel(message)
-
-		// Increment the tag version for the current commit
-		currentTag, err = IncrementVersion(currentTag, incrementLevel)
-		if err != nil {
-			return fmt.Errorf("failed to increment version for commit %s: %w", commit, err)
-		}
-
-		// Get the short hash of the commit
-		shortHash, err := git.GetShortCommitHash(commit)
-		if err != nil {
-			return fmt.Errorf("failed to get short hash for commit %s: %w", commit, err)
-		}
-
-		// Append the hash to the tag
-		tagWithHash := fmt.Sprintf("%s-%s", currentTag, shortHash)
-
-		fmt.Printf("Tagging commit %s with %s\n", commit, tagWithHash)
-
-		// Create a tag for the untagged commit
-		err = git.CreateTag(tagWithHash, fmt.Sprintf("Automated tagging for commit %s", commit), commit)
-		if err != nil {
-			return fmt.Errorf("failed to create tag %s for commit %s: %w", tagWithHash, commit, err)
-		}
+	if plan.Base == "" {
+		// No tags found; the first version is derived from v0.0.0 (or the current calendar period)
+		logging.Logger().Info("No tags found on the branch; starting from the first version.", "version", plan.Tags[0].Version.Tag(cfg.Tag.Prefix))
 	}
 
-	fmt.Println("Successfully tagged all untagged commits.")
-	return nil
+	_, err = ApplyPlan(plan, cfg)
+	return err
 }
 
-// determineIncrementLevel determines the level of version increment based on commit message.
+// withLock runs a function while holding the repository lock.
 // parameters:
-// - commitMessage: the commit message to analyze
+// - cfg: the repository configuration
+// - fn: the function to run
 // returns:
-// - string: the level of version increment (major, minor, patch)
-func determineIncrementLevel(commitMessage string) string {
-	if strings.Contains(commitMessage, "BREAKING CHANGE") {
-		return "major"
-	} else if strings.HasPrefix(commitMessage, "feat") {
-		return "minor"
-	} else if strings.HasPrefix(commitMessage, "fix") {
-		return "patch"
+// - error: the error of the function, joined with any error raised while acquiring or releasing the lock
+func withLock(cfg *config.Config, fn func() error) error {
+	l, err := lock.Acquire(context.Background(), cfg.Lock)
+	if err != nil {
+		return err
 	}
-
-	// Default to "patch" if the message doesn't match any known pattern
-	fmt.Printf("Unrecognized commit message: \"%s\". Defaulting to patch update.\n", commitMessage)
-	return "patch"
+	return errors.Join(fn(), l.Release())
 }
 
 /* utility functions
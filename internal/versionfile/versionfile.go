@@ -0,0 +1,250 @@
+package versionfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	TypeText  = "text"  // the whole file is the version
+	TypeJSON  = "json"  // a string value of a JSON document, e.g. package.json
+	TypeYAML  = "yaml"  // a scalar value of a YAML document, e.g. Chart.yaml
+	TypeRegex = "regex" // the matches of a regular expression, e.g. a Go constant
+
+	defaultKey = "version" // key of the version value in json and yaml files
+)
+
+// jsonFrame tracks an open object or array while walking the tokens of a JSON document.
+type jsonFrame struct {
+	object    bool   // whether the container is an object rather than an array
+	expectKey bool   // whether the next token of an object is a key
+	key       string // key of the current member of an object
+}
+
+// ---------- Version File Functions ----------
+
+// Update rewrites the version string of a version file, preserving the rest of the file.
+// parameters:
+// - root: the directory the path of the file is relative to (usually the repository root)
+// - file: the version file to update
+// - value: the version string to write
+// returns:
+// - bool: true if the file content changed
+// - error: an error object if the file could not be read, updated or written, otherwise nil
+func Update(root string, file config.VersionFile, value string) (bool, error) {
+	path := filepath.Join(root, file.Path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read version file %s: %w", file.Path, err)
+	}
+
+	updated, err := rewrite(content, file, value)
+	if err != nil {
+		return false, fmt.Errorf("failed to update version file %s: %w", file.Path, err)
+	}
+	if bytes.Equal(content, updated) {
+		return false, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat version file %s: %w", file.Path, err)
+	}
+	if err := os.WriteFile(path, updated, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write version file %s: %w", file.Path, err)
+	}
+	return true, nil
+}
+
+// ---------- Helper Functions ----------
+
+// rewrite replaces the version string in the content of a version file using the updater of its type.
+func rewrite(content []byte, file config.VersionFile, value string) ([]byte, error) {
+	key := file.Key
+	if key == "" {
+		key = defaultKey
+	}
+
+	switch file.Type {
+	case TypeText, "":
+		if strings.TrimSpace(string(content)) == value {
+			return content, nil
+		}
+		return []byte(value + "\n"), nil
+	case TypeJSON:
+		return rewriteJSON(content, strings.Split(key, "."), value)
+	case TypeYAML:
+		return rewriteYAML(content, strings.Split(key, "."), value)
+	case TypeRegex:
+		return rewriteRegex(content, file.Pattern, value)
+	}
+	return nil, fmt.Errorf("unknown version file type %q: expected %s, %s, %s or %s", file.Type, TypeText, TypeJSON, TypeYAML, TypeRegex)
+}
+
+// rewriteJSON replaces the string value at a key path of a JSON document in place,
+// so that the formatting and key order of the document are preserved.
+func rewriteJSON(content []byte, path []string, value string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	var stack []jsonFrame
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		if n := len(stack); n > 0 && stack[n-1].object && stack[n-1].expectKey {
+			if key, ok := tok.(string); ok {
+				stack[n-1].key, stack[n-1].expectKey = key, false
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			stack = append(stack, jsonFrame{object: tok == json.Delim('{'), expectKey: true})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		default:
+			if matchesPath(stack, path) {
+				if _, ok := tok.(string); !ok {
+					return nil, fmt.Errorf("value of %s is not a string", strings.Join(path, "."))
+				}
+
+				// the offset before the token points behind the previous token: skip separators
+				start += int64(len(content[start:]) - len(bytes.TrimLeft(content[start:], " \t\r\n:,")))
+				quoted, err := json.Marshal(value)
+				if err != nil {
+					return nil, err
+				}
+				return slices.Concat(content[:start], quoted, content[dec.InputOffset():]), nil
+			}
+		}
+
+		if n := len(stack); n > 0 {
+			stack[n-1].expectKey = true
+		}
+	}
+	return nil, fmt.Errorf("key %s not found", strings.Join(path, "."))
+}
+
+// rewriteYAML replaces the scalar value at a key path of a YAML document in place,
+// so that comments, formatting and quoting of the document are preserved.
+func rewriteYAML(content []byte, path []string, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("key %s not found", strings.Join(path, "."))
+	}
+
+	node := doc.Content[0]
+	for _, key := range path {
+		var next *yaml.Node
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					next = node.Content[i+1]
+				}
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("key %s not found", strings.Join(path, "."))
+		}
+		node = next
+	}
+	if node.Kind != yaml.ScalarNode || strings.Contains(node.Value, "\n") {
+		return nil, fmt.Errorf("value of %s is not a single-line scalar", strings.Join(path, "."))
+	}
+
+	// locate the raw scalar from its line and column
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	start := node.Column - 1
+	for _, line := range lines[:node.Line-1] {
+		start += len(line)
+	}
+
+	end, replacement := start+len(node.Value), value
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		end = start + 1 + len(scanQuoted(content[start+1:], '"'))
+		replacement = `"` + value + `"`
+	case yaml.SingleQuotedStyle:
+		end = start + 1 + len(scanQuoted(content[start+1:], '\''))
+		replacement = "'" + value + "'"
+	}
+	return slices.Concat(content[:start], []byte(replacement), content[end:]), nil
+}
+
+// rewriteRegex replaces every match of a regular expression with the version.
+// If the expression has a capture group, only the first group is replaced.
+func rewriteRegex(content []byte, pattern, value string) ([]byte, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("regex version files need a pattern")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	matches := re.FindAllSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("pattern %s not found", pattern)
+	}
+
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) > 2 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		out = append(append(out, content[last:start]...), value...)
+		last = end
+	}
+	return append(out, content[last:]...), nil
+}
+
+// matchesPath reports whether the keys of the open containers equal the given path.
+func matchesPath(stack []jsonFrame, path []string) bool {
+	if len(stack) != len(path) {
+		return false
+	}
+	for i, f := range stack {
+		if !f.object || f.key != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// scanQuoted returns the content of a quoted YAML scalar up to, and including, its closing quote.
+func scanQuoted(rest []byte, quote byte) []byte {
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case quote == '"' && rest[i] == '\\':
+			i++
+		case rest[i] == quote && quote == '\'' && i+1 < len(rest) && rest[i+1] == '\'':
+			i++
+		case rest[i] == quote:
+			return rest[:i+1]
+		}
+	}
+	return rest
+}
@@ -0,0 +1,71 @@
+package versionfile
+
+import (
+	"git-tagger/internal/config"
+	"testing"
+)
+
+// TestRewrite validates that each updater replaces only the version string and preserves the rest of the file.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     config.VersionFile
+		content  string
+		expected string
+	}{
+		{"text", config.VersionFile{}, "1.3.0\n", "1.4.0\n"},
+		{
+			"json",
+			config.VersionFile{Type: TypeJSON},
+			"{\n  \"name\": \"app\",\n  \"engines\": {\"version\": \"18\"},\n  \"version\" : \"1.3.0\",\n  \"private\": true\n}\n",
+			"{\n  \"name\": \"app\",\n  \"engines\": {\"version\": \"18\"},\n  \"version\" : \"1.4.0\",\n  \"private\": true\n}\n",
+		},
+		{
+			"json nested key",
+			config.VersionFile{Type: TypeJSON, Key: "packages..version"},
+			`{"packages": {"": {"version": "1.3.0"}}, "version": "1.3.0"}`,
+			`{"packages": {"": {"version": "1.4.0"}}, "version": "1.3.0"}`,
+		},
+		{
+			"yaml",
+			config.VersionFile{Type: TypeYAML, Key: "appVersion"},
+			"apiVersion: v2\nversion: 0.1.0 # chart\nappVersion: \"1.3.0\"\n",
+			"apiVersion: v2\nversion: 0.1.0 # chart\nappVersion: \"1.4.0\"\n",
+		},
+		{
+			"yaml nested key",
+			config.VersionFile{Type: TypeYAML, Key: "image.tag"},
+			"image:\n  repository: app\n  tag: '1.3.0'\n",
+			"image:\n  repository: app\n  tag: '1.4.0'\n",
+		},
+		{
+			"regex",
+			config.VersionFile{Type: TypeRegex, Pattern: `Version = "([^"]*)"`},
+			"package version\n\nconst Version = \"1.3.0\"\n",
+			"package version\n\nconst Version = \"1.4.0\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := rewrite([]byte(tt.content), tt.file, "1.4.0")
+		if err != nil {
+			t.Fatalf("%s: rewrite failed: %v", tt.name, err)
+		}
+		if string(got) != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+
+	if _, err := rewrite([]byte(`{"name": "app"}`), config.VersionFile{Type: TypeJSON}, "1.4.0"); err == nil {
+		t.Errorf("Expected an error for a missing key")
+	}
+	if _, err := rewrite([]byte("const Version = 1"), config.VersionFile{Type: TypeRegex, Pattern: `v(\d+)`}, "1.4.0"); err == nil {
+		t.Errorf("Expected an error for a pattern without matches")
+	}
+}
@@ -0,0 +1,329 @@
+// Package tagger is the library API of git-tagger, for embedding automatic version tagging in other
+// programs. A Tagger plans and creates the version tags of a repository, computes its next version and
+// collects release notes. Its methods take a context that cancels the git commands they run, return
+// structured results, and never print or exit; messages go to the configured logger.
+//
+// A Tagger is not independent of other Taggers: the internal packages of git-tagger keep a single git
+// backend and logger per process, which every method installs for the duration of its call. Calls of all
+// Taggers in a process therefore run one at a time, even for different repositories, so a Tagger behaves
+// like a process singleton; use separate processes to tag repositories in parallel.
+package tagger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"git-tagger/internal/config"
+	"git-tagger/internal/git"
+	"git-tagger/internal/lock"
+	"git-tagger/internal/logging"
+	"git-tagger/internal/notes"
+	"git-tagger/internal/policy"
+	"git-tagger/internal/version"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Types of the configuration, the git backend and release notes, usable without importing internal packages.
+type (
+	Config    = config.Config
+	Backend   = git.Backend
+	GitResult = git.Result
+	Notes     = notes.Notes
+	Group     = notes.Group
+	Change    = notes.Change
+)
+
+// Errors returned by Tagger methods, checkable with errors.Is.
+var (
+	ErrNoRelease    = version.ErrNoRelease
+	ErrNotARepo     = git.ErrNotARepo
+	ErrNoTags       = git.ErrNoTags
+	ErrDetachedHead = git.ErrDetachedHead
+	ErrDirtyTree    = git.ErrDirtyTree
+	ErrTagExists    = git.ErrTagExists
+	ErrLocked       = lock.ErrLocked
+	ErrNotAllowed   = policy.ErrNotAllowed
+	ErrOutsideLine  = version.ErrOutsideLine
+	ErrPlanChanged  = version.ErrPlanChanged
+)
+
+// mu serializes the calls of all Taggers while they replace the process-wide backend and logger.
+var mu sync.Mutex
+
+// Tagger tags the commits of a repository with versions. Create it with New. Its methods may be called
+// from several goroutines, but run one at a time across all Taggers of the process.
+type Tagger struct {
+	repoPath string
+	cfg      *config.Config
+	backend  git.Backend
+	logger   *slog.Logger
+}
+
+// Option configures a Tagger.
+type Option func(*Tagger)
+
+// Plan describes the tags that tagging the untagged commits of a branch would create.
+type Plan struct {
+	Branch  string // branch or revision being tagged
+	Base    string // tag the plan starts from, empty if the repository has no version tags
+	Tags    []Tag  // tags to create, oldest commit first
+	Skipped []Tag  // untagged commits that warrant no release, without a version or tag
+
+	plan *version.Plan
+}
+
+// Tag is a version tag of a commit.
+type Tag struct {
+	Name    string   // name of the tag, including the hash suffix of build tags
+	Version string   // version without prefix and hash suffix, e.g. 1.4.0
+	Commit  string   // full hash of the tagged commit
+	Subject string   // subject of the commit message
+	Level   string   // level of version increment detected from the commit message
+	Reason  string   // explanation of how the level was detected
+	Issues  []string // issues referenced by the commit message
+}
+
+// ApplyResult describes the tags created by Apply.
+type ApplyResult struct {
+	RunID string // ID of the run journal, for reverting the run with `tagger undo`
+	Tags  []Tag  // created tags, oldest commit first
+}
+
+// Version is the version a branch would be tagged with next.
+type Version struct {
+	Tag     string         // name of the tag, including the prefix, without the hash suffix
+	Version string         // version without prefix
+	Fields  map[string]any // parts of the version as used by -format templates, e.g. Major and Minor
+}
+
+// ---------- Option Functions ----------
+
+// WithRepoPath runs git in the given repository directory instead of the current directory.
+// It has no effect together with WithBackend.
+// parameters:
+// - path: a directory of the repository
+// returns:
+// - Option: the option
+func WithRepoPath(path string) Option {
+	return func(t *Tagger) { t.repoPath = path }
+}
+
+// WithConfig uses the given configuration instead of the config.yaml of the repository.
+// parameters:
+// - cfg: the configuration, e.g. from DefaultConfig or LoadConfig
+// returns:
+// - Option: the option
+func WithConfig(cfg *Config) Option {
+	return func(t *Tagger) { t.cfg = cfg }
+}
+
+// WithBackend runs git commands through the given backend instead of the git binary.
+// parameters:
+// - b: the backend
+// returns:
+// - Option: the option
+func WithBackend(b Backend) Option {
+	return func(t *Tagger) { t.backend = b }
+}
+
+// WithLogger sends the messages of the Tagger to the given logger; by default they are discarded.
+// parameters:
+// - l: the logger
+// returns:
+// - Option: the option
+func WithLogger(l *slog.Logger) Option {
+	return func(t *Tagger) { t.logger = l }
+}
+
+// ---------- Tagger Functions ----------
+
+// New creates a Tagger.
+// parameters:
+// - opts: the options of the Tagger
+// returns:
+// - *Tagger: the Tagger
+func New(opts ...Option) *Tagger {
+	t := &Tagger{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.backend == nil {
+		t.backend = &git.Runner{Dir: t.repoPath}
+	}
+	if t.logger == nil {
+		t.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return t
+}
+
+// DefaultConfig returns the configuration used for repositories without a configuration file.
+// returns:
+// - *Config: the default configuration
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// LoadConfig reads a configuration file, filling unset values with defaults.
+// parameters:
+// - path: the path to the configuration file
+// returns:
+// - *Config: the loaded configuration
+// - error: an error object if something went wrong, otherwise nil
+func LoadConfig(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// Plan computes the tags that tagging the untagged commits of a branch would create, without creating them.
+// parameters:
+// - ctx: the context of the git commands
+// - branch: the branch or revision to tag, e.g. HEAD
+// returns:
+// - *Plan: the computed plan
+// - error: an error object if something went wrong, otherwise nil
+func (t *Tagger) Plan(ctx context.Context, branch string) (*Plan, error) {
+	defer t.bind(ctx)()
+
+	cfg, err := t.config()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := version.PlanUntaggedCommits(branch, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Plan{Branch: plan.Branch, Base: plan.Base, plan: plan}
+	result.Tags = tags(plan.Tags)
+	result.Skipped = tags(plan.Skipped)
+	return result, nil
+}
+
+// Apply creates the tags of a plan, and the release commit if configured, recording them in a run
+// journal. It waits for other tagging runs as configured in the lock section, then recomputes the plan
+// and only applies it if it still creates the same tags; otherwise call Plan again. If creating a tag
+// fails, the tags already created are rolled back. If the context is cancelled, the rollback fails as
+// well; the run can then be reverted with `tagger undo`.
+// parameters:
+// - ctx: the context of the git commands
+// - plan: a plan computed by Plan
+// returns:
+//   - *ApplyResult: the run and the created tags
+//   - error: ErrNoRelease if the plan creates no tags, ErrPlanChanged if another run changed the plan,
+//     otherwise an error object if something went wrong
+func (t *Tagger) Apply(ctx context.Context, plan *Plan) (*ApplyResult, error) {
+	if plan == nil || plan.plan == nil {
+		return nil, fmt.Errorf("plan was not computed by Tagger.Plan")
+	}
+	if len(plan.Tags) == 0 {
+		return nil, ErrNoRelease
+	}
+	defer t.bind(ctx)()
+
+	cfg, err := t.config()
+	if err != nil {
+		return nil, err
+	}
+	l, err := lock.Acquire(ctx, cfg.Lock)
+	if err != nil {
+		return nil, err
+	}
+	applied, runID, err := version.ApplyUnchanged(plan.plan, nil, cfg)
+	if err = errors.Join(err, l.Release()); err != nil {
+		return nil, err
+	}
+	// the release commit may have moved the last tag
+	return &ApplyResult{RunID: runID, Tags: tags(applied.Tags)}, nil
+}
+
+// Next computes the version the untagged commits of a branch would be tagged with.
+// parameters:
+// - ctx: the context of the git commands
+// - branch: the branch or revision to tag, e.g. HEAD
+// returns:
+// - *Version: the next version
+// - error: ErrNoRelease if no commit warrants a release, otherwise an error object if something went wrong
+func (t *Tagger) Next(ctx context.Context, branch string) (*Version, error) {
+	defer t.bind(ctx)()
+
+	cfg, err := t.config()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := version.PlanUntaggedCommits(branch, cfg)
+	if err != nil {
+		return nil, err
+	}
+	next, err := plan.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &Version{Tag: next.Tag(cfg.Tag.Prefix), Version: next.String(), Fields: next.Fields()}, nil
+}
+
+// Changelog collects the release notes of a version tag.
+// parameters:
+// - ctx: the context of the git commands
+// - tag: the version tag to describe
+// - from: the tag to start from, or empty to use the preceding version tag
+// returns:
+// - *Notes: the release notes, which Render formats
+// - error: an error object if something went wrong, otherwise nil
+func (t *Tagger) Changelog(ctx context.Context, tag, from string) (*Notes, error) {
+	defer t.bind(ctx)()
+
+	cfg, err := t.config()
+	if err != nil {
+		return nil, err
+	}
+	return notes.Build(tag, from, cfg)
+}
+
+// Render renders release notes through a text/template, or as Markdown if the template is empty.
+// parameters:
+// - n: the release notes, from Changelog
+// - text: the template, see the README for its fields
+// returns:
+// - string: the rendered notes
+// - error: an error object if the template is invalid or fails, otherwise nil
+func Render(n *Notes, text string) (string, error) {
+	return notes.Render(n, text)
+}
+
+// ---------- Helper Functions ----------
+
+// bind makes the internal packages use the backend and logger of the Tagger, with the given context,
+// until the returned function is called. It holds mu meanwhile, as the backend and logger are process-wide.
+func (t *Tagger) bind(ctx context.Context) func() {
+	mu.Lock()
+	backend, logger := git.GetBackend(), logging.Logger()
+	git.SetBackend(git.WithContext(ctx, t.backend))
+	logging.SetLogger(t.logger)
+
+	return func() {
+		git.SetBackend(backend)
+		logging.SetLogger(logger)
+		mu.Unlock()
+	}
+}
+
+// config returns the configuration of the Tagger, loading the configuration of the repository if none was given.
+func (t *Tagger) config() (*config.Config, error) {
+	if t.cfg != nil {
+		return t.cfg, nil
+	}
+	return config.LoadFromRepo()
+}
+
+// tags converts planned tags into tags of the API.
+func tags(planned []version.PlannedTag) []Tag {
+	result := make([]Tag, len(planned))
+	for i, p := range planned {
+		result[i] = Tag{Name: p.Tag, Commit: p.Commit, Subject: p.Message, Level: p.Level, Reason: p.Reason, Issues: p.Issues}
+		if p.Version != nil {
+			result[i].Version = p.Version.String()
+		}
+	}
+	return result
+}
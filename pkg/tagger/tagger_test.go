@@ -0,0 +1,80 @@
+package tagger
+
+import (
+	"context"
+	"errors"
+	"git-tagger/internal/testutils"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestTagger validates planning, applying and describing a release of a repository outside the
+// current directory, that stale plans are not applied, and that cancelled contexts stop the git commands.
+//
+// Parameters:
+//   - t: Pointer to the testing framework's T struct to manage test state and support formatted logs.
+//
+// Returns:
+//   - Nothing. This function performs test assertions to ensure correctness.
+func TestTagger(t *testing.T) {
+	testutils.SetupTestRepo(t)
+	testutils.CreateAndCommitFile(t, "feature.txt", "feat: add feature (#12)")
+	repo, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(os.TempDir()); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(repo) })
+
+	tg := New(WithRepoPath(repo), WithConfig(DefaultConfig()))
+	ctx := context.Background()
+
+	next, err := tg.Next(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if next.Tag != "v0.1.0" || next.Fields["Minor"] != 1 {
+		t.Errorf("Expected next version v0.1.0, got %+v", next)
+	}
+
+	plan, err := tg.Plan(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(plan.Tags) != 2 || plan.Tags[1].Version != "0.1.0" || plan.Tags[1].Level != "minor" {
+		t.Fatalf("Unexpected plan: %+v", plan.Tags)
+	}
+
+	result, err := tg.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.RunID == "" || len(result.Tags) != 2 || !strings.HasPrefix(result.Tags[1].Name, "v0.1.0-") {
+		t.Errorf("Unexpected apply result: %+v", result)
+	}
+
+	if _, err := tg.Next(ctx, "HEAD"); !errors.Is(err, ErrNoRelease) {
+		t.Errorf("Expected ErrNoRelease after tagging, got %v", err)
+	}
+	// a plan computed before another run tagged its commits is not applied
+	if _, err := tg.Apply(ctx, plan); !errors.Is(err, ErrPlanChanged) {
+		t.Errorf("Expected ErrPlanChanged for a stale plan, got %v", err)
+	}
+
+	n, err := tg.Changelog(ctx, result.Tags[1].Name, "")
+	if err != nil {
+		t.Fatalf("Changelog failed: %v", err)
+	}
+	if len(n.Groups) != 1 || n.Groups[0].Title != "Features" || len(n.Issues) != 1 || n.Issues[0] != "#12" {
+		t.Errorf("Unexpected notes: %+v", n)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := tg.Plan(cancelled, "HEAD"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}